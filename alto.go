@@ -0,0 +1,188 @@
+package gocarina
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+)
+
+const (
+	altoNamespace      = "http://www.loc.gov/standards/alto/ns-v4#"
+	altoSchemaLocation = altoNamespace + " http://www.loc.gov/alto/v4/alto-4-2.xsd"
+)
+
+type altoDocument struct {
+	XMLName        xml.Name        `xml:"alto"`
+	Namespace      string          `xml:"xmlns,attr"`
+	XSI            string          `xml:"xmlns:xsi,attr"`
+	SchemaLocation string          `xml:"xsi:schemaLocation,attr"`
+	Description    altoDescription `xml:"Description"`
+	Page           altoPage        `xml:"Layout>Page"`
+}
+
+type altoDescription struct {
+	MeasurementUnit string            `xml:"MeasurementUnit"`
+	OCRProcessing   altoOCRProcessing `xml:"OCRProcessing"`
+}
+
+type altoOCRProcessing struct {
+	ID       string `xml:"ID,attr"` // required by the schema
+	Software string `xml:"ocrProcessingStep>processingSoftware>softwareName"`
+}
+
+type altoBox struct {
+	HPos   int `xml:"HPOS,attr"`
+	VPos   int `xml:"VPOS,attr"`
+	Width  int `xml:"WIDTH,attr"`
+	Height int `xml:"HEIGHT,attr"`
+}
+
+type altoPage struct {
+	ID         string         `xml:"ID,attr"`
+	ImageNr    int            `xml:"PHYSICAL_IMG_NR,attr"`
+	Width      int            `xml:"WIDTH,attr"`
+	Height     int            `xml:"HEIGHT,attr"`
+	PrintSpace altoPrintSpace `xml:"PrintSpace"`
+}
+
+type altoPrintSpace struct {
+	altoBox
+	Blocks []altoTextBlock `xml:"TextBlock"`
+}
+
+type altoTextBlock struct {
+	ID string `xml:"ID,attr"`
+	altoBox
+	Lines []altoTextLine `xml:"TextLine"`
+}
+
+type altoTextLine struct {
+	ID string `xml:"ID,attr"`
+	altoBox
+	Content []interface{} // altoString and altoSpace, in order
+}
+
+type altoString struct {
+	XMLName xml.Name `xml:"String"`
+	ID      string   `xml:"ID,attr"`
+	Content string   `xml:"CONTENT,attr"`
+	altoBox
+	WC     string      `xml:"WC,attr"`
+	Glyphs []altoGlyph `xml:"Glyph"`
+}
+
+type altoSpace struct {
+	XMLName xml.Name `xml:"SP"`
+	HPos    int      `xml:"HPOS,attr"`
+	VPos    int      `xml:"VPOS,attr"`
+	Width   int      `xml:"WIDTH,attr"`
+}
+
+type altoGlyph struct {
+	ID      string `xml:"ID,attr"`
+	Content string `xml:"CONTENT,attr"`
+	altoBox
+	GC       string        `xml:"GC,attr"`
+	Variants []altoVariant `xml:"Variant"`
+}
+
+type altoVariant struct {
+	Content string `xml:"CONTENT,attr"`
+	VC      string `xml:"VC,attr"`
+}
+
+// WriteALTO writes r to w as an ALTO XML document: a page holding a single block of text lines, made up of strings
+// (words) and glyphs (chars), with their positions, confidences, and the alternatives for each glyph. If r is only
+// part of a page, it is written as a page of its own.
+func WriteALTO(w io.Writer, r *Result) error {
+	page := asPage(r)
+
+	doc := altoDocument{
+		Namespace:      altoNamespace,
+		XSI:            "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: altoSchemaLocation,
+		Description: altoDescription{
+			MeasurementUnit: "pixel",
+			OCRProcessing:   altoOCRProcessing{ID: "ocr_1", Software: "gocarina"},
+		},
+		Page: altoPage{
+			ID:      "page_1",
+			ImageNr: 1,
+			Width:   page.Bounds.Dx(),
+			Height:  page.Bounds.Dy(),
+		},
+	}
+
+	var text image.Rectangle
+	for _, line := range page.Children {
+		text = text.Union(line.Bounds)
+	}
+
+	block := altoTextBlock{ID: "block_1", altoBox: newAltoBox(text)}
+	for l, line := range page.Children {
+		altoLine := altoTextLine{ID: fmt.Sprintf("line_%d", l+1), altoBox: newAltoBox(line.Bounds)}
+
+		for w, word := range line.Children {
+			if w > 0 {
+				prev := line.Children[w-1].Bounds
+				altoLine.Content = append(altoLine.Content, altoSpace{
+					HPos:  prev.Max.X,
+					VPos:  line.Bounds.Min.Y,
+					Width: word.Bounds.Min.X - prev.Max.X,
+				})
+			}
+
+			s := altoString{
+				ID:      fmt.Sprintf("string_%d_%d", l+1, w+1),
+				Content: word.Text,
+				altoBox: newAltoBox(word.Bounds),
+				WC:      altoConf(word.Confidence),
+			}
+
+			for c, char := range word.Children {
+				glyph := altoGlyph{
+					ID:      fmt.Sprintf("glyph_%d_%d_%d", l+1, w+1, c+1),
+					Content: char.Text,
+					altoBox: newAltoBox(char.Bounds),
+					GC:      altoConf(char.Confidence),
+				}
+
+				for _, alt := range char.Alternatives {
+					variant := altoVariant{Content: string(alt.Char), VC: altoConf(alt.Confidence)}
+					glyph.Variants = append(glyph.Variants, variant)
+				}
+
+				s.Glyphs = append(s.Glyphs, glyph)
+			}
+
+			altoLine.Content = append(altoLine.Content, s)
+		}
+
+		block.Lines = append(block.Lines, altoLine)
+	}
+
+	doc.Page.PrintSpace = altoPrintSpace{altoBox: newAltoBox(text), Blocks: []altoTextBlock{block}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("error writing ALTO: %s", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("error writing ALTO: %s", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newAltoBox(r image.Rectangle) altoBox {
+	return altoBox{HPos: r.Min.X, VPos: r.Min.Y, Width: r.Dx(), Height: r.Dy()}
+}
+
+// altoConf formats a confidence from 0 to 1, as ALTO expects.
+func altoConf(confidence float64) string {
+	return fmt.Sprintf("%.4f", confidence)
+}
@@ -0,0 +1,79 @@
+package gocarina
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestWriteALTO(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteALTO(&buf, sampleResult()); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Processing struct {
+			ID       string `xml:"ID,attr"`
+			Software string `xml:"ocrProcessingStep>processingSoftware>softwareName"`
+		} `xml:"Description>OCRProcessing"`
+		Page struct {
+			Width  int `xml:"WIDTH,attr"`
+			Height int `xml:"HEIGHT,attr"`
+			Lines  []struct {
+				HPos    int `xml:"HPOS,attr"`
+				VPos    int `xml:"VPOS,attr"`
+				Strings []struct {
+					Content string `xml:"CONTENT,attr"`
+					WC      string `xml:"WC,attr"`
+					Glyphs  []struct {
+						Content  string `xml:"CONTENT,attr"`
+						Variants []struct {
+							Content string `xml:"CONTENT,attr"`
+							VC      string `xml:"VC,attr"`
+						} `xml:"Variant"`
+					} `xml:"Glyph"`
+				} `xml:"String"`
+				Spaces []struct {
+					HPos  int `xml:"HPOS,attr"`
+					Width int `xml:"WIDTH,attr"`
+				} `xml:"SP"`
+			} `xml:"PrintSpace>TextBlock>TextLine"`
+		} `xml:"Layout>Page"`
+	}
+
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("expected valid XML, got: %s", err)
+	}
+
+	if p := doc.Processing; p.ID == "" || p.Software != "gocarina" {
+		t.Errorf("expected OCR processing with an ID, by gocarina, got: %+v", p)
+	}
+
+	page := doc.Page
+	if page.Width != 100 || page.Height != 60 || len(page.Lines) != 2 {
+		t.Fatalf("expected a 100x60 page of 2 lines, got: %dx%d of %d", page.Width, page.Height, len(page.Lines))
+	}
+
+	line := page.Lines[0]
+	if line.HPos != 10 || line.VPos != 10 || len(line.Strings) != 2 {
+		t.Fatalf("expected a line at 10,10 of 2 strings, got: %d,%d of %d", line.HPos, line.VPos, len(line.Strings))
+	}
+
+	if len(line.Spaces) != 1 || line.Spaces[0].HPos != 18 || line.Spaces[0].Width != 12 {
+		t.Errorf("expected a space at 18 of width 12, got: %+v", line.Spaces)
+	}
+
+	s := line.Strings[1]
+	if s.Content != "<" || s.WC != "0.6000" || len(s.Glyphs) != 1 {
+		t.Fatalf("expected string %q with WC 0.6000 of 1 glyph, got: %+v", "<", s)
+	}
+
+	if v := s.Glyphs[0].Variants; len(v) != 1 || v[0].Content != "&" || v[0].VC != "0.3000" {
+		t.Errorf("expected a variant of %q with VC 0.3000, got: %+v", "&", v)
+	}
+
+	if s := page.Lines[1].Strings; len(s) != 1 || s[0].Content != "b" {
+		t.Errorf("expected a second line of %q, got: %+v", "b", s)
+	}
+}
@@ -0,0 +1,133 @@
+package gocarina
+
+import (
+	"unicode"
+)
+
+// BoggleMinLength is the fewest letters a word may have in Boggle.
+const BoggleMinLength = 3
+
+// BoggleWord is a word found on a board by following a path of adjacent tiles.
+type BoggleWord struct {
+	Word          string
+	Tiles         []int          // indices into Board.Tiles, one per letter of Word, each adjacent to the one before
+	Substitutions []Substitution // the Wildcard tiles on the path, and the letters they stand for
+}
+
+// Adjacent returns the indices of the (up to eight) tiles that touch the tile at index i, including diagonally.
+func (b *Board) Adjacent(i int) []int {
+	row, col := b.Position(i)
+
+	var result []int
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			r, c := row+dr, col+dc
+			if (dr != 0 || dc != 0) && r >= 0 && r < b.Rows() && c >= 0 && c < b.Cols() {
+				result = append(result, b.Index(r, c))
+			}
+		}
+	}
+
+	return result
+}
+
+// BoggleFrom returns the words of at least BoggleMinLength letters that can be spelled on the board as in Boggle:
+// by starting at any tile, and moving to an adjacent tile for each letter, never using a tile twice. Each word is
+// returned once, ordered ByWordLength, with the first path found for it that uses the fewest wildcards: a Wildcard
+// tile may stand for any letter it matches.
+func (d *Dictionary) BoggleFrom(b *Board) []BoggleWord {
+	found := make(map[string]BoggleWord)
+	var words []string
+
+	used := make([]bool, len(b.Tiles))
+	var word []rune
+	var path []int
+	var step func(n *trieNode, i int)
+
+	// walk follows the trie and the board together, so paths are abandoned as soon as they spell no prefix of a word
+	var walk func(n *trieNode, i int)
+	walk = func(n *trieNode, i int) {
+		tile := b.Tiles[i]
+		if tile.Letter != Wildcard {
+			if n = n.child(unicode.ToLower(tile.Letter)); n != nil {
+				step(n, i)
+			}
+			return
+		}
+
+		// a wildcard may be any letter it matches that continues a word
+		for c := range n.children {
+			if tile.Matches(n.children[c].letter) {
+				step(&n.children[c], i)
+			}
+		}
+	}
+
+	// step extends the path to the tile at index i, played as the letter of n
+	step = func(n *trieNode, i int) {
+		used[i] = true
+		word = append(word, n.letter)
+		path = append(path, i)
+
+		if n.word && len(word) >= BoggleMinLength {
+			w := BoggleWord{Word: string(word), Tiles: append([]int(nil), path...)}
+			for j, t := range path {
+				if b.Tiles[t].Letter == Wildcard {
+					w.Substitutions = append(w.Substitutions, Substitution{Index: t, Letter: word[j]})
+				}
+			}
+
+			// keep the first path found, unless another relies on fewer wildcards
+			if prev, ok := found[w.Word]; !ok {
+				found[w.Word] = w
+				words = append(words, w.Word)
+			} else if len(w.Substitutions) < len(prev.Substitutions) {
+				found[w.Word] = w
+			}
+		}
+
+		for _, next := range b.Adjacent(i) {
+			if !used[next] {
+				walk(n, next)
+			}
+		}
+
+		used[i] = false
+		word = word[:len(word)-1]
+		path = path[:len(path)-1]
+	}
+
+	for i := range b.Tiles {
+		walk(&d.root, i)
+	}
+
+	var result []BoggleWord
+	for _, word := range Rank(words, LengthRanker, 0) {
+		result = append(result, found[word])
+	}
+
+	return result
+}
+
+// BoggleWordsFrom returns the words that can be spelled on the board using the dictionary for DefaultLanguage, as
+// for Dictionary.BoggleFrom, but ordered by r, and at most limit of them. A nil r orders words ByWordLength.
+func BoggleWordsFrom(b *Board, r Ranker, limit int) []BoggleWord {
+	if r == nil {
+		r = LengthRanker
+	}
+
+	var words []string
+	found := make(map[string]BoggleWord)
+
+	for _, w := range defaultLanguageDictionary().BoggleFrom(b) {
+		words = append(words, w.Word)
+		found[w.Word] = w
+	}
+
+	var result []BoggleWord
+	for _, word := range Rank(words, r, limit) {
+		result = append(result, found[word])
+	}
+
+	return result
+}
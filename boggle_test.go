@@ -0,0 +1,84 @@
+package gocarina
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdjacent(t *testing.T) {
+	b := NewBoard(3, 3, "abcdefghi", ".........")
+
+	var examples = []struct {
+		i        int
+		expected []int
+	}{
+		{0, []int{1, 3, 4}},
+		{4, []int{0, 1, 2, 3, 5, 6, 7, 8}},
+		{7, []int{3, 4, 5, 6, 8}},
+	}
+
+	for _, ex := range examples {
+		if actual := b.Adjacent(ex.i); !reflect.DeepEqual(ex.expected, actual) {
+			t.Errorf("%d: expected: %v, got: %v", ex.i, ex.expected, actual)
+		}
+	}
+}
+
+func TestBoggleFrom(t *testing.T) {
+	b := NewBoard(3, 3,
+		"CAT"+
+			"XEX"+
+			"ROD",
+		".........")
+
+	d := NewDictionary([]string{"cat", "tea", "ace", "act", "trod", "rod", "red", "cater", "at"})
+
+	// act needs the T after the C, which isn't adjacent; nor are the T and R of trod; at is too short
+	expected := []BoggleWord{
+		{Word: "cater", Tiles: []int{0, 1, 2, 4, 6}},
+		{Word: "ace", Tiles: []int{1, 0, 4}},
+		{Word: "cat", Tiles: []int{0, 1, 2}},
+		{Word: "red", Tiles: []int{6, 4, 8}},
+		{Word: "rod", Tiles: []int{6, 7, 8}},
+		{Word: "tea", Tiles: []int{2, 4, 1}},
+	}
+
+	if actual := d.BoggleFrom(b); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %+v, got: %+v", expected, actual)
+	}
+
+	// no tile may be used twice
+	if words := NewDictionary([]string{"eve"}).BoggleFrom(NewBoard(1, 2, "EV", "..")); len(words) != 0 {
+		t.Errorf("expected no words, got: %+v", words)
+	}
+}
+
+func TestBoggleFromWildcard(t *testing.T) {
+	b := NewBoard(2, 2, "CA?T", "....")
+	d := NewDictionary([]string{"cat", "cut", "cot"})
+
+	expected := []BoggleWord{
+		{Word: "cat", Tiles: []int{0, 1, 3}},
+		{Word: "cot", Tiles: []int{0, 2, 3}, Substitutions: []Substitution{{Index: 2, Letter: 'o'}}},
+		{Word: "cut", Tiles: []int{0, 2, 3}, Substitutions: []Substitution{{Index: 2, Letter: 'u'}}},
+	}
+
+	if actual := d.BoggleFrom(b); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %+v, got: %+v", expected, actual)
+	}
+
+	// a wildcard with candidates only stands for those
+	b.Tiles[2].Candidates = []rune{'U'}
+	if words := d.BoggleFrom(b); len(words) != 2 || words[1].Word != "cut" {
+		t.Errorf("expected %q and %q, got: %+v", "cat", "cut", words)
+	}
+}
+
+func TestBoggleWordsFrom(t *testing.T) {
+	b := NewBoard(2, 2, "QUIZ", "....")
+
+	words := BoggleWordsFrom(b, ScrabbleRanker, 1)
+	if len(words) != 1 || words[0].Word != "quiz" {
+		t.Errorf("expected just %q, got: %+v", "quiz", words)
+	}
+}
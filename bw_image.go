@@ -54,6 +54,91 @@ func BlackWhiteImage(img image.Image) image.Image {
 	return &Converted{img, bwPalette}
 }
 
+// Polarity describes whether the glyphs in an image are darker or lighter than their background.
+type Polarity int
+
+const (
+	DarkOnLight Polarity = iota // dark glyphs on a light background, which is what BoundingBox and Tile expect
+	LightOnDark                 // light glyphs on a dark background, e.g. selected or coloured tiles
+)
+
+// DetectPolarity guesses the polarity of a black & white image by sampling the pixels along its edges,
+// which are assumed to be mostly background.
+func DetectPolarity(img image.Image) Polarity {
+	b := img.Bounds()
+	black, total := 0, 0
+
+	sample := func(x, y int) {
+		total++
+		if IsBlack(img.At(x, y)) {
+			black++
+		}
+	}
+
+	for x := b.Min.X; x < b.Max.X; x++ {
+		sample(x, b.Min.Y)
+		sample(x, b.Max.Y-1)
+	}
+
+	for y := b.Min.Y + 1; y < b.Max.Y-1; y++ {
+		sample(b.Min.X, y)
+		sample(b.Max.X-1, y)
+	}
+
+	if black*2 > total {
+		return LightOnDark
+	}
+
+	return DarkOnLight
+}
+
+// Inverted swaps black and white in a black & white image.
+type Inverted struct {
+	Img image.Image
+}
+
+func (i *Inverted) ColorModel() color.Model {
+	return bwPalette
+}
+
+func (i *Inverted) Bounds() image.Rectangle {
+	return i.Img.Bounds()
+}
+
+func (i *Inverted) At(x, y int) color.Color {
+	if IsBlack(i.Img.At(x, y)) {
+		return color.White
+	}
+
+	return color.Black
+}
+
+func (i *Inverted) SubImage(r image.Rectangle) image.Image {
+	sub := i.Img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}).SubImage(r)
+
+	// preserve the inversion
+	return &Inverted{sub}
+}
+
+// InvertImage returns a view of the black & white image img with black and white swapped.
+func InvertImage(img image.Image) image.Image {
+	return &Inverted{img}
+}
+
+// NormalizePolarity quantizes img to black & white, and inverts the result if the glyphs turn out to be
+// lighter than their background. The returned image always has dark glyphs on a light background.
+func NormalizePolarity(img image.Image) image.Image {
+	bw := BlackWhiteImage(img)
+
+	if DetectPolarity(bw) == LightOnDark {
+		return InvertImage(bw)
+	}
+
+	return bw
+}
+
 func IsBlack(c color.Color) bool {
 	r, g, b, a := c.RGBA()
 
@@ -2,6 +2,7 @@ package gocarina
 
 import (
 	"image"
+	"image/draw"
 	"os"
 	"testing"
 )
@@ -29,3 +30,34 @@ func TestBlackWhiteImage(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectPolarity(t *testing.T) {
+	glyph := image.Rect(5, 4, 9, 12)
+
+	dark := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	draw.Draw(dark, dark.Bounds(), image.White, image.ZP, draw.Src)
+	draw.Draw(dark, glyph, image.Black, image.ZP, draw.Src)
+
+	light := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	draw.Draw(light, light.Bounds(), image.Black, image.ZP, draw.Src)
+	draw.Draw(light, glyph, image.White, image.ZP, draw.Src)
+
+	var examples = []struct {
+		img      image.Image
+		polarity Polarity
+	}{
+		{dark, DarkOnLight},
+		{light, LightOnDark},
+	}
+
+	for i, tt := range examples {
+		if p := DetectPolarity(BlackWhiteImage(tt.img)); p != tt.polarity {
+			t.Errorf("example %d: expected polarity %d, got: %d", i, tt.polarity, p)
+		}
+
+		// either way, the normalized image should bound to just the glyph
+		if bbox := BoundingBox(NormalizePolarity(tt.img), 0); bbox != glyph {
+			t.Errorf("example %d: expected bounding box %v, got: %v", i, glyph, bbox)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Command recognize reads the letters of Letterpress game boards from screenshots, using the network saved by
+// train, and optionally lists the words that can be formed with them, or writes each board out as hOCR or ALTO XML.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/armhold/gocarina"
+)
+
+// NetworkFile is where train saves the network.
+const NetworkFile = "ocr.save"
+
+var rankers = map[string]gocarina.Ranker{
+	"length":   gocarina.LengthRanker,
+	"scrabble": gocarina.ScrabbleRanker,
+	"rarity":   gocarina.RarityRanker,
+}
+
+func main() {
+	words := flag.Bool("w", false, "list the words that can be formed with each board")
+	rank := flag.String("rank", "length", "how to order the words: "+strings.Join(rankerNames(), ", "))
+	limit := flag.Int("limit", 50, "the most words to list for each board, or 0 for all of them")
+
+	var format gocarina.Format
+	flag.Var(&format, "format", "how to write out each board: text, hocr or alto")
+
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] board.png...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	r, ok := rankers[*rank]
+	if !ok {
+		log.Fatalf("unknown ranker %q: expected %s", *rank, strings.Join(rankerNames(), ", "))
+	}
+
+	if *words && format != gocarina.TextFormat {
+		log.Fatalf("-w can only be used with -format text")
+	}
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	n, err := gocarina.RestoreNetwork(NetworkFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, file := range flag.Args() {
+		b := gocarina.ReadUnknownBoard(file)
+		b.Recognize(n, 0, 0)
+
+		if format != gocarina.TextFormat {
+			if err := gocarina.WriteResult(os.Stdout, b.Result(n), format); err != nil {
+				log.Fatal(err)
+			}
+			continue
+		}
+
+		for row := 0; row < b.Rows(); row++ {
+			for col := 0; col < b.Cols(); col++ {
+				fmt.Printf(" %c", b.Tile(row, col).Letter)
+			}
+			fmt.Println()
+		}
+
+		if *words {
+			fmt.Print("\n\n")
+			for _, word := range gocarina.WordsFromRanked(b.Letters(), r, *limit) {
+				fmt.Println(word)
+			}
+		}
+	}
+}
+
+func rankerNames() []string {
+	var result []string
+	for name := range rankers {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
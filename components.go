@@ -0,0 +1,164 @@
+package gocarina
+
+import (
+	"image"
+)
+
+// Connectivity determines which neighbouring pixels are considered to be part of the same component.
+type Connectivity int
+
+const (
+	FourConnected  Connectivity = 4 // horizontal and vertical neighbours only
+	EightConnected Connectivity = 8 // diagonal neighbours as well
+)
+
+// Component describes a connected group of black pixels.
+type Component struct {
+	Label     int             // label of the component's pixels in Components.Labels
+	Area      int             // number of pixels in the component
+	Bounds    image.Rectangle // minimum rectangle containing the component
+	CentroidX float64         // mean x coordinate of the component's pixels
+	CentroidY float64         // mean y coordinate of the component's pixels
+}
+
+// Components is the result of labeling the black pixels of an image.
+type Components struct {
+	Rect   image.Rectangle // bounds of the labeled image
+	Labels []int           // component label for each pixel in row-major order, or 0 for white pixels
+	List   []Component     // the components, ordered by label: List[i].Label == i+1
+}
+
+// LabelComponents finds the connected groups of black pixels in src, using the given connectivity.
+func LabelComponents(src image.Image, conn Connectivity) *Components {
+	r := src.Bounds()
+	w, h := r.Dx(), r.Dy()
+
+	c := &Components{Rect: r, Labels: make([]int, w*h)}
+
+	black := make([]bool, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			black[y*w+x] = IsBlack(src.At(r.Min.X+x, r.Min.Y+y))
+		}
+	}
+
+	neighbours := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	if conn == EightConnected {
+		neighbours = append(neighbours, [2]int{-1, -1}, [2]int{1, -1}, [2]int{-1, 1}, [2]int{1, 1})
+	}
+
+	var stack []int
+
+	for i := range black {
+		if !black[i] || c.Labels[i] != 0 {
+			continue
+		}
+
+		// flood-fill a new component, starting from pixel i
+		label := len(c.List) + 1
+		comp := Component{Label: label}
+		sumX, sumY := 0, 0
+		minX, minY, maxX, maxY := w, h, -1, -1
+
+		c.Labels[i] = label
+		stack = append(stack[:0], i)
+
+		for len(stack) > 0 {
+			p := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			x, y := p%w, p/w
+			comp.Area++
+			sumX += x
+			sumY += y
+
+			if x < minX {
+				minX = x
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if y > maxY {
+				maxY = y
+			}
+
+			for _, n := range neighbours {
+				nx, ny := x+n[0], y+n[1]
+				if nx < 0 || nx >= w || ny < 0 || ny >= h {
+					continue
+				}
+
+				q := ny*w + nx
+				if black[q] && c.Labels[q] == 0 {
+					c.Labels[q] = label
+					stack = append(stack, q)
+				}
+			}
+		}
+
+		comp.Bounds = image.Rect(minX, minY, maxX+1, maxY+1).Add(r.Min)
+		comp.CentroidX = float64(r.Min.X) + float64(sumX)/float64(comp.Area)
+		comp.CentroidY = float64(r.Min.Y) + float64(sumY)/float64(comp.Area)
+		c.List = append(c.List, comp)
+	}
+
+	return c
+}
+
+// LabelAt returns the component label for the pixel at (x, y), or 0 if the pixel is white or out of bounds.
+func (c *Components) LabelAt(x, y int) int {
+	if !(image.Point{x, y}).In(c.Rect) {
+		return 0
+	}
+
+	return c.Labels[(y-c.Rect.Min.Y)*c.Rect.Dx()+(x-c.Rect.Min.X)]
+}
+
+// GlyphBoundingBox is like BoundingBox, but is not thrown off by stray black pixels. Components with an area
+// below minArea are dropped as specks. The largest remaining component is taken to be the main body of the glyph,
+// and other components lying directly above or below it (e.g. the dot on an 'i' or 'j') are included too.
+//
+// If no component survives, the bounds of src are returned.
+func GlyphBoundingBox(src image.Image, minArea int, border int) image.Rectangle {
+	comps := LabelComponents(src, EightConnected)
+
+	var main *Component
+	for i, comp := range comps.List {
+		if comp.Area >= minArea && (main == nil || comp.Area > main.Area) {
+			main = &comps.List[i]
+		}
+	}
+
+	if main == nil {
+		return src.Bounds()
+	}
+
+	result := main.Bounds
+	maxGap := main.Bounds.Dy() / 2
+
+	for _, comp := range comps.List {
+		if comp.Label == main.Label || comp.Area < minArea {
+			continue
+		}
+
+		// must overlap the main body horizontally...
+		if comp.Bounds.Max.X <= main.Bounds.Min.X || comp.Bounds.Min.X >= main.Bounds.Max.X {
+			continue
+		}
+
+		// ...and be vertically close to it
+		gap := main.Bounds.Min.Y - comp.Bounds.Max.Y
+		if comp.Bounds.Min.Y >= main.Bounds.Max.Y {
+			gap = comp.Bounds.Min.Y - main.Bounds.Max.Y
+		}
+
+		if gap <= maxGap {
+			result = result.Union(comp.Bounds)
+		}
+	}
+
+	return result.Inset(-border)
+}
@@ -0,0 +1,74 @@
+package gocarina
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestLabelComponents(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+
+	// two pixels touching only at a corner, plus a 2x2 square elsewhere
+	img.Set(1, 1, color.Black)
+	img.Set(2, 2, color.Black)
+	draw.Draw(img, image.Rect(5, 5, 7, 7), image.Black, image.ZP, draw.Src)
+
+	var examples = []struct {
+		conn  Connectivity
+		count int
+	}{
+		{FourConnected, 3},
+		{EightConnected, 2},
+	}
+
+	for _, tt := range examples {
+		comps := LabelComponents(img, tt.conn)
+		if len(comps.List) != tt.count {
+			t.Errorf("%d-connected: expected %d components, got: %d", tt.conn, tt.count, len(comps.List))
+		}
+	}
+
+	comps := LabelComponents(img, EightConnected)
+	square := comps.List[comps.LabelAt(5, 5)-1]
+
+	if square.Area != 4 {
+		t.Errorf("expected area 4, got: %d", square.Area)
+	}
+
+	if square.Bounds != image.Rect(5, 5, 7, 7) {
+		t.Errorf("expected bounds %v, got: %v", image.Rect(5, 5, 7, 7), square.Bounds)
+	}
+
+	if square.CentroidX != 5.5 || square.CentroidY != 5.5 {
+		t.Errorf("expected centroid (5.5, 5.5), got: (%f, %f)", square.CentroidX, square.CentroidY)
+	}
+
+	if comps.LabelAt(0, 0) != 0 {
+		t.Errorf("expected white pixel to be unlabeled")
+	}
+}
+
+func TestGlyphBoundingBox(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+
+	// an 'i': a stem with a dot above it
+	draw.Draw(img, image.Rect(14, 12, 18, 28), image.Black, image.ZP, draw.Src)
+	draw.Draw(img, image.Rect(14, 6, 18, 10), image.Black, image.ZP, draw.Src)
+
+	// stray specks that would ruin a naive bounding box
+	img.Set(1, 1, color.Black)
+	img.Set(30, 30, color.Black)
+
+	expected := image.Rect(14, 6, 18, 28)
+	if bbox := GlyphBoundingBox(img, 4, 0); bbox != expected {
+		t.Errorf("expected %v, got: %v", expected, bbox)
+	}
+
+	if bbox := GlyphBoundingBox(img, 4, 1); bbox != expected.Inset(-1) {
+		t.Errorf("expected %v, got: %v", expected.Inset(-1), bbox)
+	}
+}
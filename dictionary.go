@@ -0,0 +1,535 @@
+package gocarina
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultDictionaryFile = "words-en.txt" // the word list embedded as the default dictionary
+	DefaultLanguage       = "en"           // the language of the default dictionary
+)
+
+//go:embed words-en.txt
+var defaultWords string
+
+// Dictionary is a list of words held in a trie, so that every word that can be formed from a pool of letters can
+// be found without testing each word in the list in turn.
+//
+// To match the words a game actually accepts, combine a dictionary with an allow list and a block list, e.g.
+// d.Union(allow).Difference(block).
+type Dictionary struct {
+	root  trieNode
+	count int
+}
+
+type trieNode struct {
+	letter   rune
+	word     bool       // true if the path from the root to this node spells a word
+	children []trieNode // in alphabetical order; held by value, so that scanning them doesn't miss the cache
+}
+
+// child returns the child node for the given letter, or nil if there is none.
+func (n *trieNode) child(letter rune) *trieNode {
+	for i := range n.children {
+		if n.children[i].letter == letter {
+			return &n.children[i]
+		}
+	}
+
+	return nil
+}
+
+// NewDictionary returns a dictionary of the given words. Words are lower-cased, and duplicates are ignored.
+func NewDictionary(words []string) *Dictionary {
+	d := &Dictionary{}
+	for _, word := range words {
+		d.Add(word)
+	}
+
+	return d
+}
+
+// ReadDictionary reads a dictionary with one word per line. Blank lines, and lines starting with '#', are ignored.
+func ReadDictionary(r io.Reader) (*Dictionary, error) {
+	d := &Dictionary{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" && !strings.HasPrefix(word, "#") {
+			d.Add(word)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading dictionary: %s", err)
+	}
+
+	return d, nil
+}
+
+// LoadDictionary reads a dictionary from a file, as for ReadDictionary.
+func LoadDictionary(filePath string) (*Dictionary, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening dictionary: %s", err)
+	}
+	defer file.Close()
+
+	return ReadDictionary(file)
+}
+
+var (
+	defaultDictionary     *Dictionary
+	defaultDictionaryOnce sync.Once
+)
+
+// DefaultDictionary returns the English dictionary from DefaultDictionaryFile, which is embedded in the binary,
+// so it doesn't matter what directory the program is run from. The dictionary is only built the first time.
+func DefaultDictionary() *Dictionary {
+	defaultDictionaryOnce.Do(func() {
+		d, err := ReadDictionary(strings.NewReader(defaultWords))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		defaultDictionary = d
+	})
+
+	return defaultDictionary
+}
+
+var (
+	dictionariesMu sync.RWMutex
+	dictionaries   = make(map[string]*Dictionary)
+)
+
+// RegisterDictionary makes d the dictionary for the given language, e.g. "fr", replacing any registered before.
+// Registering a dictionary for DefaultLanguage replaces DefaultDictionary everywhere no dictionary is given: in
+// WordsFrom, Solver, Query, and the other package-level searches.
+func RegisterDictionary(lang string, d *Dictionary) {
+	dictionariesMu.Lock()
+	defer dictionariesMu.Unlock()
+
+	dictionaries[lang] = d
+}
+
+// DictionaryFor returns the dictionary registered for the given language. DefaultDictionary is used for
+// DefaultLanguage, unless another has been registered in its place.
+func DictionaryFor(lang string) (*Dictionary, bool) {
+	dictionariesMu.RLock()
+	d, ok := dictionaries[lang]
+	dictionariesMu.RUnlock()
+
+	if !ok && lang == DefaultLanguage {
+		return DefaultDictionary(), true
+	}
+
+	return d, ok
+}
+
+// defaultLanguageDictionary returns the dictionary for DefaultLanguage, which is used wherever no dictionary is given.
+func defaultLanguageDictionary() *Dictionary {
+	d, _ := DictionaryFor(DefaultLanguage)
+	return d
+}
+
+// Languages returns the languages for which a dictionary is available, in alphabetical order.
+func Languages() []string {
+	dictionariesMu.RLock()
+	defer dictionariesMu.RUnlock()
+
+	result := []string{DefaultLanguage}
+	for lang := range dictionaries {
+		if lang != DefaultLanguage {
+			result = append(result, lang)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+// Add adds word to the dictionary.
+func (d *Dictionary) Add(word string) {
+	if word == "" {
+		return
+	}
+
+	n := &d.root
+
+	for _, c := range strings.ToLower(word) {
+		next := n.child(c)
+		if next == nil {
+			i := sort.Search(len(n.children), func(i int) bool { return n.children[i].letter > c })
+
+			n.children = append(n.children, trieNode{})
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = trieNode{letter: c}
+
+			next = &n.children[i]
+		}
+
+		n = next
+	}
+
+	if !n.word {
+		n.word = true
+		d.count++
+	}
+}
+
+// Remove removes word from the dictionary, if present.
+func (d *Dictionary) Remove(word string) {
+	n := &d.root
+
+	for _, c := range strings.ToLower(word) {
+		if n = n.child(c); n == nil {
+			return
+		}
+	}
+
+	if n.word {
+		n.word = false
+		d.count--
+	}
+}
+
+// Union returns a new dictionary of the words in d, together with those in each of the others.
+func (d *Dictionary) Union(others ...*Dictionary) *Dictionary {
+	result := NewDictionary(d.Words())
+	for _, other := range others {
+		for _, word := range other.Words() {
+			result.Add(word)
+		}
+	}
+
+	return result
+}
+
+// Intersect returns a new dictionary of the words in d that are also in every one of the others.
+func (d *Dictionary) Intersect(others ...*Dictionary) *Dictionary {
+	result := &Dictionary{}
+
+	for _, word := range d.Words() {
+		found := true
+		for _, other := range others {
+			if !other.Contains(word) {
+				found = false
+				break
+			}
+		}
+
+		if found {
+			result.Add(word)
+		}
+	}
+
+	return result
+}
+
+// Difference returns a new dictionary of the words in d that are in none of the others.
+func (d *Dictionary) Difference(others ...*Dictionary) *Dictionary {
+	result := NewDictionary(d.Words())
+	for _, other := range others {
+		for _, word := range other.Words() {
+			result.Remove(word)
+		}
+	}
+
+	return result
+}
+
+// Contains returns true if word is in the dictionary.
+func (d *Dictionary) Contains(word string) bool {
+	n := &d.root
+
+	for _, c := range strings.ToLower(word) {
+		if n = n.child(c); n == nil {
+			return false
+		}
+	}
+
+	return n.word
+}
+
+// Len returns the number of words in the dictionary.
+func (d *Dictionary) Len() int {
+	return d.count
+}
+
+// Words returns every word in the dictionary, in alphabetical order.
+func (d *Dictionary) Words() []string {
+	// the trie is walked in alphabetical order, so there is no need to sort
+	var result []string
+
+	var walk func(n *trieNode, prefix []rune)
+	walk = func(n *trieNode, prefix []rune) {
+		if n.word {
+			result = append(result, string(prefix))
+		}
+
+		for i := range n.children {
+			c := &n.children[i]
+			walk(c, append(prefix, c.letter))
+		}
+	}
+	walk(&d.root, nil)
+
+	return result
+}
+
+// WordsFrom returns the words that can be constructed from the given chars, as for CanMakeWordFrom, ordered
+// ByWordLength.
+//
+// Rather than testing every word, it walks the trie, only descending into letters that are still left in the pool.
+// So whole branches of the dictionary are skipped as soon as they need a letter that isn't there. And as the trie
+// is walked in alphabetical order, the words found need only be grouped by length to be ordered ByWordLength.
+//
+// Each Wildcard in chars may stand for any letter.
+func (d *Dictionary) WordsFrom(chars string) []string {
+	return d.wordsFrom(newLetterPool(chars))
+}
+
+// wordsFrom is like WordsFrom, for the letters in pool.
+func (d *Dictionary) wordsFrom(pool *letterPool) []string {
+	// byLength[i] holds the words of i letters
+	var byLength [][]string
+
+	d.search(context.Background(), pool, func(word []rune, _ []bool) bool {
+		for len(byLength) <= len(word) {
+			byLength = append(byLength, nil)
+		}
+		byLength[len(word)] = append(byLength[len(word)], string(word))
+
+		return true
+	})
+
+	var result []string
+	for i := len(byLength) - 1; i >= 0; i-- {
+		result = append(result, byLength[i]...)
+	}
+
+	return result
+}
+
+// CheckContextEvery is how many trie nodes search visits between checks for the cancellation of its context.
+const CheckContextEvery = 1024
+
+// search walks the trie for the words that can be constructed from the letters in pool, calling fn with each in
+// alphabetical order. A wildcard from the pool is only used for a letter once the pool has run out of it; wild
+// reports which letters of the word used one, and pool.assign which wildcard. The slices passed to fn are only
+// valid until it returns.
+//
+// The search stops early if fn returns false, or if ctx is done, in which case ctx.Err() is returned.
+func (d *Dictionary) search(ctx context.Context, pool *letterPool, fn func(word []rune, wild []bool) bool) error {
+	var word []rune
+	var wild []bool
+	var err error
+	visited := 0
+
+	var walk func(n *trieNode) bool
+	walk = func(n *trieNode) bool {
+		if visited++; visited%CheckContextEvery == 0 {
+			if err = ctx.Err(); err != nil {
+				return false
+			}
+		}
+
+		if n.word && !fn(word, wild) {
+			return false
+		}
+
+		for i := range n.children {
+			c := &n.children[i]
+
+			w := false
+			if !pool.take(c.letter) {
+				if !pool.takeWild(c.letter) {
+					continue
+				}
+				w = true
+			}
+
+			word = append(word, c.letter)
+			wild = append(wild, w)
+			more := walk(c)
+			word = word[:len(word)-1]
+			wild = wild[:len(wild)-1]
+
+			if w {
+				pool.putWild()
+			} else {
+				pool.put(c.letter)
+			}
+
+			if !more {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	walk(&d.root)
+
+	return err
+}
+
+// letterPool counts the letters available to spell words with. ASCII letters are counted in an array, as a map
+// lookup for every node visited in the trie is comparatively slow.
+type letterPool struct {
+	ascii [128]int
+	other map[rune]int
+
+	wilds      []string // the letters each wildcard may stand for; "" for any letter
+	needs      []rune   // the letters currently standing in for wildcards
+	restricted bool     // true if any wildcard is limited to particular letters
+}
+
+// newLetterPool returns a pool of the given chars, in which each Wildcard may stand for any letter.
+func newLetterPool(chars string) *letterPool {
+	p := &letterPool{other: make(map[rune]int)}
+	for _, c := range strings.ToLower(chars) {
+		if c == Wildcard {
+			p.addWild("")
+		} else {
+			p.put(c)
+		}
+	}
+
+	return p
+}
+
+// addWild adds a wildcard to the pool, which may stand for any of the given letters, or for any letter at all if
+// letters is empty.
+func (p *letterPool) addWild(letters string) {
+	p.wilds = append(p.wilds, strings.ToLower(letters))
+	if letters != "" {
+		p.restricted = true
+	}
+}
+
+// take removes one instance of c from the pool, returning false if there is none.
+func (p *letterPool) take(c rune) bool {
+	if c < 128 {
+		if p.ascii[c] == 0 {
+			return false
+		}
+		p.ascii[c]--
+		return true
+	}
+
+	if p.other[c] == 0 {
+		return false
+	}
+	p.other[c]--
+	return true
+}
+
+// put adds one instance of c to the pool.
+func (p *letterPool) put(c rune) {
+	if c < 128 {
+		p.ascii[c]++
+	} else {
+		p.other[c]++
+	}
+}
+
+// takeWild uses a wildcard for c, returning false if there is no wildcard left that could stand for it.
+func (p *letterPool) takeWild(c rune) bool {
+	if len(p.needs) == len(p.wilds) {
+		return false
+	}
+
+	p.needs = append(p.needs, c)
+
+	// with restricted wildcards, it matters which wildcard stands for which letter
+	if p.restricted && p.assign() == nil {
+		p.needs = p.needs[:len(p.needs)-1]
+		return false
+	}
+
+	return true
+}
+
+// putWild returns the wildcard most recently taken.
+func (p *letterPool) putWild() {
+	p.needs = p.needs[:len(p.needs)-1]
+}
+
+// assign returns the index of the wildcard standing in for each of the letters taken with takeWild, or nil if
+// there's no way to assign a different wildcard to each.
+func (p *letterPool) assign() []int {
+	return matchWildcards(len(p.needs), len(p.wilds), func(i, j int) bool {
+		return p.wilds[j] == "" || strings.ContainsRune(p.wilds[j], p.needs[i])
+	})
+}
+
+// matchWildcards assigns each of n letters a different one of m wildcards, such that allows(letter, wildcard) is
+// true for each pair. It returns the index of the wildcard for each letter, or nil if there's no such assignment.
+// Wildcards are preferred in the order given. This is bipartite matching, by augmenting paths: quick enough for the
+// handful of wildcards on a board.
+func matchWildcards(n, m int, allows func(letter, wildcard int) bool) []int {
+	letterFor := make([]int, m)
+	for j := range letterFor {
+		letterFor[j] = -1
+	}
+
+	return extendMatching(n, m, letterFor, allows)
+}
+
+// extendMatching is like matchWildcards, but starts from a partial assignment: letterFor[j] is the letter already
+// assigned wildcard j, or -1. The assignment may be rearranged, but a wildcard assigned a letter stays assigned one.
+func extendMatching(n, m int, letterFor []int, allows func(letter, wildcard int) bool) []int {
+	matched := make([]bool, n)
+	for _, i := range letterFor {
+		if i >= 0 {
+			matched[i] = true
+		}
+	}
+
+	var augment func(i int, seen []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for j := 0; j < m; j++ {
+			if seen[j] || !allows(i, j) {
+				continue
+			}
+			seen[j] = true
+
+			if letterFor[j] < 0 || augment(letterFor[j], seen) {
+				letterFor[j] = i
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for i := 0; i < n; i++ {
+		if !matched[i] && !augment(i, make([]bool, m)) {
+			return nil
+		}
+	}
+
+	result := make([]int, n)
+	for j, i := range letterFor {
+		if i >= 0 {
+			result[i] = j
+		}
+	}
+
+	return result
+}
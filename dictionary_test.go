@@ -0,0 +1,257 @@
+package gocarina
+
+import (
+	"bufio"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// a full Letterpress board's worth of letters
+const benchmarkLetters = "ctsmaetrosliwnrkeeqpdnaij"
+
+func TestDictionary(t *testing.T) {
+	d := NewDictionary([]string{"bear", "Bare", "bar", "bare", "beard", "a"})
+
+	if d.Len() != 5 {
+		t.Errorf("expected 5 words, got: %d", d.Len())
+	}
+
+	var examples = []struct {
+		word string
+		out  bool
+	}{
+		{"bear", true},
+		{"BARE", true},
+		{"bea", false},
+		{"beards", false},
+		{"", false},
+	}
+
+	for _, ex := range examples {
+		if actual := d.Contains(ex.word); actual != ex.out {
+			t.Errorf("error for %q: wanted %t, got: %t", ex.word, ex.out, actual)
+		}
+	}
+
+	expected := []string{"a", "bar", "bare", "bear", "beard"}
+	if actual := d.Words(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	expected = []string{"bare", "bear", "bar", "a"}
+	if actual := d.WordsFrom("BEARX"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestDictionaryWildcards(t *testing.T) {
+	d := NewDictionary([]string{"bear", "beer", "boar", "bee", "be"})
+
+	// boar would need three wildcards
+	expected := []string{"bear", "beer", "bee", "be"}
+	if actual := d.WordsFrom("b?e?"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	// restricted wildcards must each stand for one of their own letters
+	pool := newLetterPool("br")
+	pool.addWild("ae")
+	pool.addWild("o")
+
+	expected = []string{"boar", "be"}
+	if actual := d.wordsFrom(pool); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestReadDictionary(t *testing.T) {
+	d, err := ReadDictionary(strings.NewReader("# a comment\nChat\n\n  chien \nchat\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"chat", "chien"}
+	if actual := d.Words(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	f, err := ioutil.TempFile("", "dictionary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString("chat\nchien\n")
+	f.Close()
+
+	loaded, err := LoadDictionary(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := loaded.Words(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	if _, err := LoadDictionary(f.Name() + ".missing"); err == nil {
+		t.Errorf("expected an error loading a missing file")
+	}
+}
+
+func TestDictionarySetOperations(t *testing.T) {
+	a := NewDictionary([]string{"bar", "bare", "bear"})
+	b := NewDictionary([]string{"bear", "beard"})
+
+	var examples = []struct {
+		name     string
+		d        *Dictionary
+		expected []string
+	}{
+		{"union", a.Union(b), []string{"bar", "bare", "bear", "beard"}},
+		{"intersect", a.Intersect(b), []string{"bear"}},
+		{"difference", a.Difference(b), []string{"bar", "bare"}},
+	}
+
+	for _, ex := range examples {
+		if actual := ex.d.Words(); !reflect.DeepEqual(ex.expected, actual) {
+			t.Errorf("%s: expected: %v, got: %v", ex.name, ex.expected, actual)
+		}
+
+		if ex.d.Len() != len(ex.expected) {
+			t.Errorf("%s: expected %d words, got: %d", ex.name, len(ex.expected), ex.d.Len())
+		}
+	}
+
+	if a.Len() != 3 || b.Len() != 2 {
+		t.Errorf("expected the original dictionaries to be left alone")
+	}
+
+	// a block list removes words the game doesn't accept, even from the middle of the trie
+	a.Remove("bar")
+	a.Remove("barn")
+
+	if a.Contains("bar") || !a.Contains("bare") || a.Len() != 2 {
+		t.Errorf("expected only %q to be removed, got: %v", "bar", a.Words())
+	}
+
+	expected := []string{"bare", "bear"}
+	if actual := a.WordsFrom("bare"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestDictionaryFor(t *testing.T) {
+	saved := dictionaries
+	defer func() { dictionaries = saved }()
+	dictionaries = make(map[string]*Dictionary)
+
+	if d, ok := DictionaryFor(DefaultLanguage); !ok || d != DefaultDictionary() {
+		t.Errorf("expected the default dictionary for %q", DefaultLanguage)
+	}
+
+	if _, ok := DictionaryFor("fr"); ok {
+		t.Errorf("expected no dictionary for %q", "fr")
+	}
+
+	fr := NewDictionary([]string{"chat"})
+	RegisterDictionary("fr", fr)
+
+	if d, ok := DictionaryFor("fr"); !ok || d != fr {
+		t.Errorf("expected the registered dictionary for %q", "fr")
+	}
+
+	expected := []string{"en", "fr"}
+	if actual := Languages(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestRegisterDefaultLanguage(t *testing.T) {
+	saved := dictionaries
+	defer func() { dictionaries = saved }()
+	dictionaries = make(map[string]*Dictionary)
+
+	RegisterDictionary(DefaultLanguage, NewDictionary([]string{"chat"}))
+
+	// the searches that don't name a dictionary use the one registered in place of the default
+	if actual := WordsFrom("tach"); !reflect.DeepEqual(actual, []string{"chat"}) {
+		t.Errorf("WordsFrom: expected [chat], got: %v", actual)
+	}
+
+	if actual, err := NewQuery("tach").Words(); err != nil || !reflect.DeepEqual(actual, []string{"chat"}) {
+		t.Errorf("Query: expected [chat], got: %v (%v)", actual, err)
+	}
+
+	if actual := ScrabbleWordsFrom("tach", nil, 0); len(actual) != 1 || actual[0].Word != "chat" {
+		t.Errorf("ScrabbleWordsFrom: expected [chat], got: %v", actual)
+	}
+}
+
+func TestSolverDictionary(t *testing.T) {
+	b := NewBoard(1, 4, "CHAT", "....")
+
+	s := NewSolver(b, Blue)
+	s.Dictionary = NewDictionary([]string{"chat", "chien"})
+
+	plays := s.Plays()
+	if len(plays) != 1 || plays[0].Word != "chat" {
+		t.Errorf("expected only %q, got: %+v", "chat", plays)
+	}
+}
+
+// scanWordsFrom is the original implementation of WordsFrom, which reads the whole word list and tests each word
+// with CanMakeWordFrom. It is kept to check and benchmark the Dictionary against.
+func scanWordsFrom(chars string) []string {
+	chars = strings.ToLower(chars)
+
+	var result []string
+
+	// iterate every word in the list. NB: words are already lower-cased in the list.
+	scanner := bufio.NewScanner(strings.NewReader(defaultWords))
+	for scanner.Scan() {
+		word := scanner.Text()
+		if CanMakeWordFrom(word, chars) {
+			result = append(result, word)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	sort.Sort(ByWordLength(result))
+
+	return result
+}
+
+func TestDictionaryMatchesScan(t *testing.T) {
+	// the scan tests each word with CanMakeWordFrom, so the two must agree on what a wildcard can stand for too
+	for _, chars := range []string{"bear", "door", "letterpress", benchmarkLetters, "b?ar", "ca?t", "??"} {
+		expected := scanWordsFrom(chars)
+		actual := DefaultDictionary().WordsFrom(chars)
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%q: expected %d words, got: %d", chars, len(expected), len(actual))
+		}
+	}
+}
+
+func BenchmarkDictionaryWordsFrom(b *testing.B) {
+	d := DefaultDictionary()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		d.WordsFrom(benchmarkLetters)
+	}
+}
+
+func BenchmarkScanWordsFrom(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		scanWordsFrom(benchmarkLetters)
+	}
+}
@@ -0,0 +1,371 @@
+// Package engine models the state of a game of Letterpress, and searches it for good moves.
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/armhold/gocarina"
+)
+
+const (
+	WinScore     = 1000.0 // the value of a won game; larger than any lead in tiles
+	DefaultWidth = 8      // number of moves searched at each ply, if a strategy doesn't say
+)
+
+var (
+	ErrGameOver = errors.New("the game is over")
+	ErrNoMoves  = errors.New("no moves available")
+)
+
+// State is a position in a game of Letterpress.
+type State struct {
+	Board      *gocarina.Board      // the letters and ownership of the tiles
+	ToMove     gocarina.Owner       // the player whose turn it is
+	Session    *gocarina.Session    // words played so far, which (along with their prefixes) may not be played again
+	Words      []string             // candidate words; if nil, every word in Dictionary that can be formed from the board's letters
+	Dictionary *gocarina.Dictionary // defaults to the dictionary for gocarina.DefaultLanguage
+}
+
+// NewState returns the state of the game on the given board. The board's letters should already have been
+// recognized, e.g. by running the tiles of a board from gocarina.ReadUnknownBoard through a trained network.
+func NewState(b *gocarina.Board, toMove gocarina.Owner) *State {
+	return &State{Board: b, ToMove: toMove, Session: gocarina.NewSession()}
+}
+
+// Score returns the number of tiles owned by the given player.
+func (s *State) Score(player gocarina.Owner) int {
+	score := 0
+	for _, tile := range s.Board.Tiles {
+		if tile.Owner == player {
+			score++
+		}
+	}
+
+	return score
+}
+
+// GameOver returns true once every tile has been claimed.
+func (s *State) GameOver() bool {
+	return s.Score(gocarina.Unowned) == 0
+}
+
+// Winner returns the player with the most tiles at the end of the game, or Unowned if the game is tied or not over.
+func (s *State) Winner() gocarina.Owner {
+	if !s.GameOver() {
+		return gocarina.Unowned
+	}
+
+	blue, red := s.Score(gocarina.Blue), s.Score(gocarina.Red)
+	switch {
+	case blue > red:
+		return gocarina.Blue
+	case red > blue:
+		return gocarina.Red
+	}
+
+	return gocarina.Unowned
+}
+
+// Moves returns the legal plays for the player to move, best first as ordered by gocarina.ByPlayScore.
+func (s *State) Moves() []gocarina.Play {
+	if s.GameOver() {
+		return nil
+	}
+
+	solver := gocarina.Solver{Board: s.Board, Player: s.ToMove, Words: s.Words, Dictionary: s.Dictionary, Session: s.Session}
+	plays := solver.Plays()
+
+	// the candidate words depend only on the board's letters, which don't change during the game
+	s.Words = solver.Words
+
+	return plays
+}
+
+// Apply returns the state after the player to move makes the given play. The receiver is left unchanged.
+func (s *State) Apply(p gocarina.Play) (*State, error) {
+	if s.GameOver() {
+		return nil, ErrGameOver
+	}
+
+	session := gocarina.NewSession()
+	if s.Session != nil {
+		session = s.Session.Copy()
+	}
+
+	if err := session.Record(p.Word); err != nil {
+		return nil, err
+	}
+
+	word := []rune(p.Word)
+	if len(word) != len(p.Tiles) {
+		return nil, fmt.Errorf("%q needs %d tiles, got: %d", p.Word, len(word), len(p.Tiles))
+	}
+
+	used := make(map[int]bool)
+	for i, t := range p.Tiles {
+		if t < 0 || t >= len(s.Board.Tiles) || used[t] {
+			return nil, fmt.Errorf("invalid tiles for %q: %v", p.Word, p.Tiles)
+		}
+		used[t] = true
+
+		if !s.Board.Tiles[t].Matches(word[i]) {
+			return nil, fmt.Errorf("tile %d is not a %c", t, word[i])
+		}
+	}
+
+	next := &State{
+		Board:      s.Board.Apply(p, s.ToMove),
+		ToMove:     s.ToMove.Opponent(),
+		Session:    session,
+		Words:      s.Words,
+		Dictionary: s.Dictionary,
+	}
+
+	return next, nil
+}
+
+// Evaluator scores a state from the point of view of the given player: the higher, the better for them.
+type Evaluator interface {
+	Evaluate(s *State, player gocarina.Owner) float64
+}
+
+// EvaluatorFunc adapts an ordinary function to the Evaluator interface.
+type EvaluatorFunc func(s *State, player gocarina.Owner) float64
+
+func (f EvaluatorFunc) Evaluate(s *State, player gocarina.Owner) float64 {
+	return f(s, player)
+}
+
+// ScoreDelta evaluates a state as the player's lead in tiles over the opponent. A finished game is worth WinScore
+// (plus the lead) to the winner, and as much less to the loser.
+var ScoreDelta Evaluator = EvaluatorFunc(func(s *State, player gocarina.Owner) float64 {
+	return endgame(s, player) + float64(s.Score(player)-s.Score(player.Opponent()))
+})
+
+// DefendedDelta is like ScoreDelta, but counts each locked tile Weight times over, as locked tiles can't be
+// taken back by the opponent.
+type DefendedDelta struct {
+	Weight float64
+}
+
+func (d DefendedDelta) Evaluate(s *State, player gocarina.Owner) float64 {
+	result := endgame(s, player)
+
+	for _, tile := range s.Board.Tiles {
+		v := 1.0
+		if tile.Locked {
+			v = d.Weight
+		}
+
+		switch tile.Owner {
+		case player:
+			result += v
+		case player.Opponent():
+			result -= v
+		}
+	}
+
+	return result
+}
+
+// endgame returns WinScore if the game is over and the player has won, -WinScore if they have lost, and 0 otherwise.
+func endgame(s *State, player gocarina.Owner) float64 {
+	switch s.Winner() {
+	case player:
+		return WinScore
+	case player.Opponent():
+		return -WinScore
+	}
+
+	return 0
+}
+
+// Strategy chooses a move for the player to move.
+type Strategy interface {
+	Choose(s *State) (gocarina.Play, error)
+}
+
+// Greedy chooses the move that leads to the best evaluated state, without looking any further ahead.
+type Greedy struct {
+	Eval Evaluator // defaults to ScoreDelta
+}
+
+func (g Greedy) Choose(s *State) (gocarina.Play, error) {
+	eval := evaluator(g.Eval)
+
+	return choose(s, s.Moves(), func(next *State) float64 {
+		return eval.Evaluate(next, s.ToMove)
+	})
+}
+
+// Minimax searches Depth moves ahead, assuming that the opponent always replies with the move that is worst for the
+// player, as judged by Eval. Only the Width best moves at each turn (as ordered by State.Moves) are searched.
+type Minimax struct {
+	Eval  Evaluator // defaults to ScoreDelta
+	Depth int       // defaults to 2: the player's move, and the opponent's reply
+	Width int       // defaults to DefaultWidth
+}
+
+func (m Minimax) Choose(s *State) (gocarina.Play, error) {
+	player := s.ToMove
+	alpha := math.Inf(-1)
+
+	return choose(s, limit(s.Moves(), m.Width), func(next *State) float64 {
+		v := m.value(next, player, depth(m.Depth)-1, alpha, math.Inf(1))
+		alpha = math.Max(alpha, v)
+		return v
+	})
+}
+
+// value returns the minimax value of the state for player, using alpha-beta pruning.
+func (m Minimax) value(s *State, player gocarina.Owner, depth int, alpha, beta float64) float64 {
+	// leaves are evaluated without generating their moves, which takes a full pass of the solver
+	if depth <= 0 || s.GameOver() {
+		return evaluator(m.Eval).Evaluate(s, player)
+	}
+
+	moves := limit(s.Moves(), m.Width)
+	if len(moves) == 0 {
+		return evaluator(m.Eval).Evaluate(s, player)
+	}
+
+	maximizing := s.ToMove == player
+
+	best := math.Inf(1)
+	if maximizing {
+		best = math.Inf(-1)
+	}
+
+	for _, p := range moves {
+		next, err := s.Apply(p)
+		if err != nil {
+			continue
+		}
+
+		v := m.value(next, player, depth-1, alpha, beta)
+
+		if maximizing {
+			best = math.Max(best, v)
+			alpha = math.Max(alpha, v)
+		} else {
+			best = math.Min(best, v)
+			beta = math.Min(beta, v)
+		}
+
+		if alpha >= beta {
+			break
+		}
+	}
+
+	return best
+}
+
+// Expectimax is like Minimax, but rather than assuming the worst, it assumes that the opponent picks at random
+// between their Width best moves.
+type Expectimax struct {
+	Eval  Evaluator // defaults to ScoreDelta
+	Depth int       // defaults to 2: the player's move, and the opponent's reply
+	Width int       // defaults to DefaultWidth
+}
+
+func (e Expectimax) Choose(s *State) (gocarina.Play, error) {
+	player := s.ToMove
+
+	return choose(s, limit(s.Moves(), e.Width), func(next *State) float64 {
+		return e.value(next, player, depth(e.Depth)-1)
+	})
+}
+
+// value returns the expectimax value of the state for player.
+func (e Expectimax) value(s *State, player gocarina.Owner, depth int) float64 {
+	// leaves are evaluated without generating their moves, which takes a full pass of the solver
+	if depth <= 0 || s.GameOver() {
+		return evaluator(e.Eval).Evaluate(s, player)
+	}
+
+	moves := limit(s.Moves(), e.Width)
+	if len(moves) == 0 {
+		return evaluator(e.Eval).Evaluate(s, player)
+	}
+
+	best, sum, count := math.Inf(-1), 0.0, 0
+
+	for _, p := range moves {
+		next, err := s.Apply(p)
+		if err != nil {
+			continue
+		}
+
+		v := e.value(next, player, depth-1)
+		best = math.Max(best, v)
+		sum += v
+		count++
+	}
+
+	if count == 0 {
+		return evaluator(e.Eval).Evaluate(s, player)
+	}
+
+	if s.ToMove == player {
+		return best
+	}
+
+	return sum / float64(count)
+}
+
+// choose returns the move whose resulting state has the highest value. Ties go to the earlier move.
+func choose(s *State, moves []gocarina.Play, value func(next *State) float64) (gocarina.Play, error) {
+	if s.GameOver() {
+		return gocarina.Play{}, ErrGameOver
+	}
+
+	var best gocarina.Play
+	bestValue, found := math.Inf(-1), false
+
+	for _, p := range moves {
+		next, err := s.Apply(p)
+		if err != nil {
+			continue
+		}
+
+		if v := value(next); !found || v > bestValue {
+			best, bestValue, found = p, v, true
+		}
+	}
+
+	if !found {
+		return gocarina.Play{}, ErrNoMoves
+	}
+
+	return best, nil
+}
+
+func evaluator(e Evaluator) Evaluator {
+	if e == nil {
+		return ScoreDelta
+	}
+
+	return e
+}
+
+func depth(d int) int {
+	if d <= 0 {
+		return 2
+	}
+
+	return d
+}
+
+func limit(moves []gocarina.Play, width int) []gocarina.Play {
+	if width <= 0 {
+		width = DefaultWidth
+	}
+
+	if len(moves) > width {
+		return moves[:width]
+	}
+
+	return moves
+}
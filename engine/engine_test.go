@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/armhold/gocarina"
+)
+
+// newTestState builds a state with blue to move, on a board as for gocarina.NewBoard.
+func newTestState(rows, cols int, letters, owners string, words ...string) *State {
+	s := NewState(gocarina.NewBoard(rows, cols, letters, owners), gocarina.Blue)
+	s.Words = words
+
+	return s
+}
+
+func TestApply(t *testing.T) {
+	s := newTestState(1, 4, "cats", "....", "cat", "cats", "at")
+
+	cat := gocarina.Play{Word: "cat", Tiles: []int{0, 1, 2}}
+	next, err := s.Apply(cat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next.ToMove != gocarina.Red || next.Score(gocarina.Blue) != 3 || next.GameOver() {
+		t.Errorf("expected red to move with blue on 3 tiles, got %s to move with %d", next.ToMove, next.Score(gocarina.Blue))
+	}
+
+	if s.Score(gocarina.Blue) != 0 {
+		t.Errorf("expected Apply to leave the original state alone")
+	}
+
+	if _, err := next.Apply(cat); err == nil {
+		t.Errorf("expected an error replaying %q", cat.Word)
+	}
+
+	if _, err := next.Apply(gocarina.Play{Word: "at", Tiles: []int{1, 3}}); err == nil {
+		t.Errorf("expected an error for a tile that doesn't match its letter")
+	}
+
+	for _, p := range next.Moves() {
+		if p.Word == cat.Word {
+			t.Errorf("expected %q not to be offered again", cat.Word)
+		}
+	}
+
+	last, err := next.Apply(gocarina.Play{Word: "cats", Tiles: []int{0, 1, 2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// c and a are locked by blue, so red only captures the t
+	if !last.GameOver() || last.Winner() != gocarina.Unowned || last.Score(gocarina.Red) != 2 {
+		t.Errorf("expected a tied game, got game over: %t, winner: %s", last.GameOver(), last.Winner())
+	}
+
+	if _, err := (Greedy{}).Choose(last); err != ErrGameOver {
+		t.Errorf("expected %v, got: %v", ErrGameOver, err)
+	}
+}
+
+func TestApplyPrefix(t *testing.T) {
+	s := newTestState(1, 4, "cats", "....", "cat", "cats")
+
+	next, err := s.Apply(gocarina.Play{Word: "cats", Tiles: []int{0, 1, 2, 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if moves := next.Moves(); len(moves) != 0 {
+		t.Errorf("expected no moves once %q is played, got: %+v", "cats", moves)
+	}
+
+	if _, err := next.Apply(gocarina.Play{Word: "cat", Tiles: []int{0, 1, 2}}); err == nil {
+		t.Errorf("expected an error playing a prefix of %q", "cats")
+	}
+}
+
+func TestMinimaxLooksAhead(t *testing.T) {
+	// a b c    . b .
+	// d e f    . . .
+	s := newTestState(2, 3, "abcdef", ".b....", "b", "ab", "cfeb", "adbe")
+
+	// greedily taking the most tiles leaves b and e unprotected, so red can end the game with "cfeb" and win
+	greedy, err := Greedy{}.Choose(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if greedy.Word != "adbe" {
+		t.Errorf("expected greedy to choose %q, got: %q", "adbe", greedy.Word)
+	}
+
+	next, _ := s.Apply(greedy)
+	reply, err := Minimax{}.Choose(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if last, _ := next.Apply(reply); last.Winner() != gocarina.Red {
+		t.Errorf("expected red to win by replying %q, got winner: %s", reply.Word, last.Winner())
+	}
+
+	for _, strategy := range []Strategy{Minimax{}, Minimax{Eval: DefendedDelta{Weight: 2}}, Expectimax{Depth: 2}} {
+		p, err := strategy.Choose(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if p.Word == "adbe" {
+			t.Errorf("%T: expected to avoid %q", strategy, p.Word)
+		}
+	}
+}
+
+func TestLeavesNotExpanded(t *testing.T) {
+	// Moves caches the candidate words in the state, so a leaf that generated its moves would be left with some
+	s := newTestState(1, 4, "cats", "....")
+
+	Minimax{}.value(s, gocarina.Blue, 0, math.Inf(-1), math.Inf(1))
+	Expectimax{}.value(s, gocarina.Blue, 0)
+
+	if s.Words != nil {
+		t.Errorf("expected leaves to be evaluated without generating moves, got words: %v", s.Words)
+	}
+}
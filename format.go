@@ -0,0 +1,66 @@
+package gocarina
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is a way of writing out a Result. It implements flag.Value, so it can be chosen with a command-line flag.
+type Format int
+
+const (
+	TextFormat Format = iota // just the text
+	HOCRFormat               // hOCR, i.e. HTML marked up with the layout of the text
+	ALTOFormat               // ALTO XML
+)
+
+var formatNames = map[Format]string{
+	TextFormat: "text",
+	HOCRFormat: "hocr",
+	ALTOFormat: "alto",
+}
+
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+
+	return "unknown"
+}
+
+// Set sets f to the format with the given name: text, hocr or alto.
+func (f *Format) Set(name string) error {
+	for format, n := range formatNames {
+		if strings.EqualFold(name, n) {
+			*f = format
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown format %q: expected text, hocr or alto", name)
+}
+
+// WriteResult writes r to w in the given format. If r is only part of a page, it is written as a page of its own.
+func WriteResult(w io.Writer, r *Result, f Format) error {
+	switch f {
+	case TextFormat:
+		_, err := fmt.Fprintln(w, r.Text)
+		return err
+	case HOCRFormat:
+		return WriteHOCR(w, r)
+	case ALTOFormat:
+		return WriteALTO(w, r)
+	}
+
+	return fmt.Errorf("unknown format: %d", f)
+}
+
+// asPage returns r as a page, wrapping it in a word, line and page as needed.
+func asPage(r *Result) *Result {
+	for r.Level > PageLevel {
+		r = newResult(r.Level-1, []*Result{r}, "")
+	}
+
+	return r
+}
@@ -0,0 +1,81 @@
+package gocarina
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"testing"
+)
+
+// sampleResult returns a page of two lines: "a<" and "b", where '<' was nearly read as '&'.
+func sampleResult() *Result {
+	char := func(text string, r image.Rectangle, confidence float64, alts ...Candidate) *Result {
+		return &Result{Level: CharLevel, Text: text, Bounds: r, Confidence: confidence, Alternatives: alts, Row: -1, Col: -1}
+	}
+
+	a := char("a", image.Rect(10, 10, 18, 20), 0.9)
+	lt := char("<", image.Rect(30, 10, 38, 20), 0.6, Candidate{'&', 0.3})
+	b := char("b", image.Rect(10, 40, 18, 50), 0.8)
+
+	line1 := newResult(LineLevel, []*Result{newResult(WordLevel, []*Result{a}, ""),
+		newResult(WordLevel, []*Result{lt}, "")}, " ")
+	line2 := newResult(LineLevel, []*Result{newResult(WordLevel, []*Result{b}, "")}, " ")
+
+	page := newResult(PageLevel, []*Result{line1, line2}, "\n")
+	page.Bounds = image.Rect(0, 0, 100, 60)
+
+	return page
+}
+
+func TestFormatFlag(t *testing.T) {
+	examples := []struct {
+		args     []string
+		expected Format
+		ok       bool
+	}{
+		{[]string{}, TextFormat, true},
+		{[]string{"-format", "hocr"}, HOCRFormat, true},
+		{[]string{"-format=ALTO"}, ALTOFormat, true},
+		{[]string{"-format", "pdf"}, TextFormat, false},
+	}
+
+	for _, ex := range examples {
+		var format Format
+		flags := flag.NewFlagSet("recognize", flag.ContinueOnError)
+		flags.SetOutput(&bytes.Buffer{})
+		flags.Var(&format, "format", "output format: text, hocr or alto")
+
+		err := flags.Parse(ex.args)
+		if (err == nil) != ex.ok || format != ex.expected {
+			t.Errorf("%v: expected %s (ok: %t), got: %s (%v)", ex.args, ex.expected, ex.ok, format, err)
+		}
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResult(&buf, sampleResult(), TextFormat); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "a <\nb\n"; buf.String() != expected {
+		t.Errorf("expected %q, got: %q", expected, buf.String())
+	}
+
+	if err := WriteResult(&buf, sampleResult(), Format(99)); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
+
+func TestAsPage(t *testing.T) {
+	char := sampleResult().Chars()[1]
+
+	page := asPage(char)
+	if page.Level != PageLevel || page.Text != "<" || page.Bounds != char.Bounds {
+		t.Errorf("expected a page of just %q at %v, got: %s %q at %v", "<", char.Bounds, page.Level, page.Text, page.Bounds)
+	}
+
+	if chars := page.Chars(); len(chars) != 1 || chars[0] != char {
+		t.Errorf("expected the page to hold the char, got: %v", chars)
+	}
+}
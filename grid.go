@@ -0,0 +1,124 @@
+package gocarina
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+const (
+	MaxTileSkewPercent  = 0.03 // how far the width and height of detected tiles may differ before DetectGrid gives up
+	MinTileEdgesPercent = 0.25 // the fraction of the edges between detected tiles that must show a change of color
+	MinTileContrast     = 3    // min summed RGB difference (0..255 per channel) for a change of color between tiles
+)
+
+// Grid describes where the tiles of a board lie within an image.
+type Grid struct {
+	Origin   image.Point // top-left corner of the top-left tile
+	TileSize int         // width and height of each (square) tile, in pixels
+	Rows     int         // number of tiles down
+	Cols     int         // number of tiles across
+}
+
+// Bounds returns the rectangle covered by the grid.
+func (g Grid) Bounds() image.Rectangle {
+	return image.Rect(g.Origin.X, g.Origin.Y, g.Origin.X+g.Cols*g.TileSize, g.Origin.Y+g.Rows*g.TileSize)
+}
+
+// TileRect returns the rectangle covered by the tile at the given row and column.
+func (g Grid) TileRect(row, col int) image.Rectangle {
+	min := g.Origin.Add(image.Pt(col*g.TileSize, row*g.TileSize))
+	return image.Rect(min.X, min.Y, min.X+g.TileSize, min.Y+g.TileSize)
+}
+
+func (g Grid) String() string {
+	return fmt.Sprintf("%dx%d tiles of %dpx at %v", g.Cols, g.Rows, g.TileSize, g.Origin)
+}
+
+// DetectGrid finds a grid of rows x cols square tiles in img, at whatever resolution and position it appears.
+//
+// The grid is taken to be the largest connected region of pixels that differ from the background color. If that
+// region does not have the proportions of the grid, the image is assumed to have been cropped to the grid itself.
+// Either way, the region must look tiled: see tiled.
+func DetectGrid(img image.Image, rows, cols int) (Grid, error) {
+	bounds := img.Bounds()
+
+	if _, largest := largestForeground(img); largest != nil {
+		if g, ok := fitGrid(largest.Bounds, rows, cols); ok && tiled(img, g) {
+			return g, nil
+		}
+	}
+
+	if g, ok := fitGrid(bounds, rows, cols); ok && tiled(img, g) {
+		return g, nil
+	}
+
+	return Grid{}, fmt.Errorf("no %dx%d grid found in %dx%d image", cols, rows, bounds.Dx(), bounds.Dy())
+}
+
+// fitGrid returns a grid of rows x cols tiles filling r, provided the tiles come out square.
+func fitGrid(r image.Rectangle, rows, cols int) (Grid, bool) {
+	tileW := float64(r.Dx()) / float64(cols)
+	tileH := float64(r.Dy()) / float64(rows)
+
+	if tileW < 1 || math.Abs(tileW-tileH) > MaxTileSkewPercent*tileW {
+		return Grid{}, false
+	}
+
+	g := Grid{
+		Origin:   r.Min,
+		TileSize: int(math.Floor((tileW+tileH)/2 + 0.5)),
+		Rows:     rows,
+		Cols:     cols,
+	}
+
+	return g, true
+}
+
+// tiled returns true if img looks like a grid of tiles where g lies. Along a line through the top of each row of
+// tiles, and down a line through the left of each column (clear of the letters), the color must change at
+// MinTileEdgesPercent of the edges between tiles, and seldom anywhere else. Not every edge shows a change, as
+// neighbouring tiles claimed by the same player are the same color.
+func tiled(img image.Image, g Grid) bool {
+	inset := g.TileSize / 8
+	tolerance := g.TileSize/20 + 1
+
+	edges, changed, stray := 0, 0, 0
+
+	// scan follows the line n tiles long from start, one step at a time
+	scan := func(start, step image.Point, n int) {
+		hit := make([]bool, n+1)
+
+		for i := 1; i < n*g.TileSize; i++ {
+			p, prev := start.Add(step.Mul(i)), start.Add(step.Mul(i-1))
+			if colorDistance(img.At(p.X, p.Y), img.At(prev.X, prev.Y)) <= MinTileContrast {
+				continue
+			}
+
+			edge := (i + g.TileSize/2) / g.TileSize
+			if d := i - edge*g.TileSize; d >= -tolerance && d <= tolerance {
+				hit[edge] = true
+			} else {
+				stray++
+			}
+		}
+
+		// the outer edges of the grid don't count
+		for _, h := range hit[1:n] {
+			edges++
+			if h {
+				changed++
+			}
+		}
+	}
+
+	for row := 0; row < g.Rows; row++ {
+		scan(g.TileRect(row, 0).Min.Add(image.Pt(0, inset)), image.Pt(1, 0), g.Cols)
+	}
+
+	for col := 0; col < g.Cols; col++ {
+		scan(g.TileRect(0, col).Min.Add(image.Pt(inset, 0)), image.Pt(0, 1), g.Rows)
+	}
+
+	return edges > 0 && float64(changed) >= MinTileEdgesPercent*float64(edges) && stray <= changed
+}
@@ -0,0 +1,102 @@
+package gocarina
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestDetectGrid(t *testing.T) {
+	img := readImage("board-images/board1.png")
+
+	// the same board on a taller, higher resolution screen
+	scaled := Scale(img, image.Rect(0, 0, 750, 1334))
+
+	// the grid alone, as if the screenshot had been cropped
+	cropped := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}).SubImage(image.Rect(0, 496, 640, 1136))
+
+	// the grid with a margin all around it, as on a tablet
+	padded := image.NewRGBA(image.Rect(0, 0, 900, 1400))
+	draw.Draw(padded, padded.Bounds(), &image.Uniform{BackgroundColor(img)}, image.ZP, draw.Src)
+	draw.Draw(padded, image.Rect(130, 600, 770, 1240), img, image.Pt(0, 496), draw.Src)
+
+	var examples = []struct {
+		name string
+		img  image.Image
+		grid Grid
+	}{
+		{"original", img, Grid{image.Pt(0, 496), 128, 5, 5}},
+		{"scaled", scaled, Grid{image.Pt(0, 583), 150, 5, 5}},
+		{"cropped", cropped, Grid{image.Pt(0, 496), 128, 5, 5}},
+		{"padded", padded, Grid{image.Pt(130, 600), 128, 5, 5}},
+	}
+
+	for _, tt := range examples {
+		actual, err := DetectGrid(tt.img, 5, 5)
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+
+		// allow a pixel or so for rounding in Scale
+		if abs(actual.Origin.X-tt.grid.Origin.X) > 1 || abs(actual.Origin.Y-tt.grid.Origin.Y) > 1 ||
+			abs(actual.TileSize-tt.grid.TileSize) > 1 || actual.Rows != tt.grid.Rows || actual.Cols != tt.grid.Cols {
+			t.Errorf("%s: expected grid %v, got: %v", tt.name, tt.grid, actual)
+		}
+	}
+}
+
+func TestDetectGridNotABoard(t *testing.T) {
+	// a square that isn't tiled at all
+	square := image.NewRGBA(image.Rect(0, 0, 400, 400))
+	draw.Draw(square, square.Bounds(), &image.Uniform{color.White}, image.ZP, draw.Src)
+	draw.Draw(square, image.Rect(50, 50, 350, 350), &image.Uniform{color.RGBA{200, 30, 30, 0xff}}, image.ZP, draw.Src)
+
+	// a square that changes color everywhere
+	noise := image.NewRGBA(image.Rect(0, 0, 400, 400))
+	r := rand.New(rand.NewSource(1))
+	for y := 0; y < 400; y++ {
+		for x := 0; x < 400; x++ {
+			noise.Set(x, y, color.Gray{uint8(r.Intn(256))})
+		}
+	}
+
+	var examples = []struct {
+		name       string
+		img        image.Image
+		rows, cols int
+	}{
+		{"square", square, 5, 5},
+		{"noise", noise, 5, 5},
+		{"wrong size", readImage("board-images/board1.png"), 4, 4},
+	}
+
+	for _, tt := range examples {
+		if g, err := DetectGrid(tt.img, tt.rows, tt.cols); err == nil {
+			t.Errorf("%s: expected no grid, got: %v", tt.name, g)
+		}
+	}
+}
+
+func TestReadUnknownBoardAnyResolution(t *testing.T) {
+	img := readImage("board-images/board1.png")
+
+	padded := image.NewRGBA(image.Rect(0, 0, 900, 1400))
+	draw.Draw(padded, padded.Bounds(), &image.Uniform{BackgroundColor(img)}, image.ZP, draw.Src)
+	draw.Draw(padded, image.Rect(130, 600, 770, 1240), img, image.Pt(0, 496), draw.Src)
+
+	file := writeTempPNG(padded, t)
+	defer os.Remove(file)
+
+	b := ReadUnknownBoard(file)
+	if b.Grid.Origin != image.Pt(130, 600) {
+		t.Errorf("expected grid at %v, got: %v", image.Pt(130, 600), b.Grid.Origin)
+	}
+
+	assertSimilarTiles(ReadUnknownBoard("board-images/board1.png"), b, 0, t)
+}
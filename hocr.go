@@ -0,0 +1,95 @@
+package gocarina
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"image"
+	"io"
+	"strings"
+)
+
+var hocrTemplate = template.Must(template.New("hocr").Funcs(template.FuncMap{
+	"bbox":   hocrBBox,
+	"coords": hocrCoords,
+	"conf":   hocrConf,
+	"inc":    func(i int) int { return i + 1 },
+	"text":   hocrText,
+}).Parse(`<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN" "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en" lang="en">
+<head>
+<title></title>
+<meta http-equiv="Content-Type" content="text/html; charset=utf-8" />
+<meta name="ocr-system" content="gocarina" />
+<meta name="ocr-capabilities" content="ocr_page ocr_line ocrx_word ocrx_cinfo" />
+</head>
+<body>
+<div class="ocr_page" id="page_1" title="{{bbox .Bounds}}">
+{{- range $l, $line := .Children}}
+<span class="ocr_line" id="line_1_{{inc $l}}" title="{{bbox $line.Bounds}}">
+{{- range $w, $word := $line.Children}}
+<span class="ocrx_word" id="word_1_{{inc $l}}_{{inc $w}}" title="{{bbox $word.Bounds}}; x_wconf {{conf $word.Confidence}}">
+{{- range $word.Children -}}
+<span class="ocrx_cinfo" title="x_bboxes {{coords .Bounds}}; x_conf {{conf .Confidence}}">
+{{- if .Alternatives -}}
+<span class="alternatives"><ins class="alt" title="x_conf {{conf .Confidence}}">{{text .Text}}</ins>
+{{- range .Alternatives}}<del class="alt" title="x_conf {{conf .Confidence}}">{{text (printf "%c" .Char)}}</del>{{end -}}
+</span>
+{{- else}}{{text .Text}}{{end -}}
+</span>
+{{- end -}}
+</span>
+{{- end}}
+</span>
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+// WriteHOCR writes r to w as an hOCR document: HTML with a span for each line, word and char, giving their bounding
+// boxes and confidences, and the alternatives for each char. If r is only part of a page, it is written as a page
+// of its own.
+func WriteHOCR(w io.Writer, r *Result) error {
+	// html/template would escape the XML declaration, so it's written separately
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("error writing hOCR: %s", err)
+	}
+
+	if err := hocrTemplate.Execute(w, asPage(r)); err != nil {
+		return fmt.Errorf("error writing hOCR: %s", err)
+	}
+
+	return nil
+}
+
+// hocrCoords returns r as hOCR coordinates: left, top, right and bottom.
+func hocrCoords(r image.Rectangle) string {
+	return fmt.Sprintf("%d %d %d %d", r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+// hocrBBox returns r as an hOCR bbox property.
+func hocrBBox(r image.Rectangle) string {
+	return "bbox " + hocrCoords(r)
+}
+
+// hocrConf returns a confidence from 0 to 1 as a percentage, as hOCR expects.
+func hocrConf(confidence float64) string {
+	return fmt.Sprintf("%.2f", 100*confidence)
+}
+
+// hocrText returns s with each character that XML doesn't allow, such as a control character the network read a
+// glyph as, replaced by U+FFFD, as encoding/xml does.
+func hocrText(s string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c == '\t' || c == '\n' || c == '\r',
+			c >= 0x20 && c <= 0xD7FF,
+			c >= 0xE000 && c <= 0xFFFD,
+			c >= 0x10000 && c <= 0x10FFFF:
+			return c
+		}
+
+		return '\uFFFD'
+	}, s)
+}
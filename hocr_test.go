@@ -0,0 +1,105 @@
+package gocarina
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteHOCR(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHOCR(&buf, sampleResult()); err != nil {
+		t.Fatal(err)
+	}
+
+	// hOCR is XHTML, so it should be well-formed XML; collect the title of each element by its class
+	titles := make(map[string][]string)
+	var text []string
+
+	decoder := xml.NewDecoder(&buf)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expected well-formed XHTML, got: %s", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			var class, title string
+			for _, attr := range token.Attr {
+				switch attr.Name.Local {
+				case "class":
+					class = attr.Value
+				case "title":
+					title = attr.Value
+				}
+			}
+			titles[class] = append(titles[class], title)
+		case xml.CharData:
+			if s := strings.TrimSpace(string(token)); s != "" {
+				text = append(text, s)
+			}
+		}
+	}
+
+	examples := []struct {
+		class    string
+		expected []string
+	}{
+		{"ocr_page", []string{"bbox 0 0 100 60"}},
+		{"ocr_line", []string{"bbox 10 10 38 20", "bbox 10 40 18 50"}},
+		{"ocrx_word", []string{"bbox 10 10 18 20; x_wconf 90.00", "bbox 30 10 38 20; x_wconf 60.00",
+			"bbox 10 40 18 50; x_wconf 80.00"}},
+		{"ocrx_cinfo", []string{"x_bboxes 10 10 18 20; x_conf 90.00", "x_bboxes 30 10 38 20; x_conf 60.00",
+			"x_bboxes 10 40 18 50; x_conf 80.00"}},
+		{"alt", []string{"x_conf 60.00", "x_conf 30.00"}},
+	}
+
+	for _, ex := range examples {
+		if strings.Join(titles[ex.class], "|") != strings.Join(ex.expected, "|") {
+			t.Errorf("%s: expected %q, got: %q", ex.class, ex.expected, titles[ex.class])
+		}
+	}
+
+	if expected := "a|<|&|b"; strings.Join(text, "|") != expected {
+		t.Errorf("expected text %q, got: %q", expected, strings.Join(text, "|"))
+	}
+}
+
+func TestWriteHOCRInvalidChars(t *testing.T) {
+	// the network may read a glyph as a control character, which XML doesn't allow
+	char := charResult(image.Rect(0, 0, 8, 10), []Candidate{{'\x01', 0.5}, {'\x02', 0.4}})
+
+	var buf bytes.Buffer
+	if err := WriteHOCR(&buf, char); err != nil {
+		t.Fatal(err)
+	}
+
+	var text []string
+	decoder := xml.NewDecoder(&buf)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expected well-formed XHTML, got: %s", err)
+		}
+
+		if data, ok := token.(xml.CharData); ok {
+			if s := strings.TrimSpace(string(data)); s != "" {
+				text = append(text, s)
+			}
+		}
+	}
+
+	if expected := "\uFFFD|\uFFFD"; strings.Join(text, "|") != expected {
+		t.Errorf("expected text %q, got: %q", expected, strings.Join(text, "|"))
+	}
+}
@@ -101,6 +101,161 @@ func Scale(src image.Image, r image.Rectangle) image.Image {
 	return dst
 }
 
+// ScaleToFit scales the black & white src image to fit within the given rectangle using Nearest Neighbor,
+// preserving its aspect ratio. Like the MNIST digits, the result has a one pixel white margin, and the glyph
+// is positioned so that its centre of mass lies at the centre of the rectangle.
+func ScaleToFit(src image.Image, r image.Rectangle) image.Image {
+	sb := src.Bounds()
+	box := r.Inset(1)
+
+	scale := math.Min(float64(box.Dx())/float64(sb.Dx()), float64(box.Dy())/float64(sb.Dy()))
+	w := int(math.Max(1, math.Floor(float64(sb.Dx())*scale+0.5)))
+	h := int(math.Max(1, math.Floor(float64(sb.Dy())*scale+0.5)))
+
+	glyph := Scale(src, image.Rect(0, 0, w, h))
+
+	// find the centre of mass of the black pixels, defaulting to the geometric centre
+	cx, cy := float64(w)/2, float64(h)/2
+	sumX, sumY, count := 0.0, 0.0, 0
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if IsBlack(glyph.At(x, y)) {
+				sumX += float64(x) + 0.5
+				sumY += float64(y) + 0.5
+				count++
+			}
+		}
+	}
+
+	if count > 0 {
+		cx, cy = sumX/float64(count), sumY/float64(count)
+	}
+
+	// shift the centre of mass to the centre, but never push the glyph outside of the rectangle
+	clamp := func(v, lo, hi int) int {
+		if v < lo {
+			return lo
+		}
+		if v > hi {
+			return hi
+		}
+		return v
+	}
+
+	x := clamp(r.Min.X+int(math.Floor(float64(r.Dx())/2-cx+0.5)), r.Min.X, r.Max.X-w)
+	y := clamp(r.Min.Y+int(math.Floor(float64(r.Dy())/2-cy+0.5)), r.Min.Y, r.Max.Y-h)
+
+	dst := image.NewRGBA(r)
+	draw.Draw(dst, r, image.White, image.ZP, draw.Src)
+	draw.Draw(dst, image.Rect(x, y, x+w, y+h), glyph, image.ZP, draw.Src)
+
+	return dst
+}
+
+// MaxSkewDegrees bounds the rotation that EstimateSkew will search for.
+const MaxSkewDegrees = 10.0
+
+// Rotate rotates src counter-clockwise by the given angle (in degrees) about its centre, using Nearest Neighbor.
+// The result has the same bounds as src; areas uncovered by the rotation are filled with bg.
+func Rotate(src image.Image, degrees float64, bg color.Color) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(b)
+
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	cx := float64(b.Min.X) + float64(b.Dx())/2
+	cy := float64(b.Min.Y) + float64(b.Dy())/2
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			// map each destination pixel back to its source by rotating the other way
+			dx := float64(x) + 0.5 - cx
+			dy := float64(y) + 0.5 - cy
+			srcX := int(math.Floor(cx + dx*cos - dy*sin))
+			srcY := int(math.Floor(cy + dx*sin + dy*cos))
+
+			if (image.Point{srcX, srcY}).In(b) {
+				dst.Set(x, y, src.At(srcX, srcY))
+			} else {
+				dst.Set(x, y, bg)
+			}
+		}
+	}
+
+	return dst
+}
+
+// EstimateSkew estimates the angle (in degrees, counter-clockwise) by which the content of src has been rotated
+// away from upright, up to MaxSkewDegrees either way. It uses projection profiles: the black pixels are projected
+// onto the vertical axis at each candidate angle, and the angle at which rows of glyphs line up most sharply wins.
+func EstimateSkew(src image.Image) float64 {
+	bw := BlackWhiteImage(src)
+	b := bw.Bounds()
+	cx := float64(b.Min.X) + float64(b.Dx())/2
+	cy := float64(b.Min.Y) + float64(b.Dy())/2
+
+	var xs, ys []float64
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if IsBlack(bw.At(x, y)) {
+				xs = append(xs, float64(x)+0.5-cx)
+				ys = append(ys, float64(y)+0.5-cy)
+			}
+		}
+	}
+
+	if len(xs) == 0 {
+		return 0
+	}
+
+	offset := math.Hypot(float64(b.Dx()), float64(b.Dy()))
+	profile := make([]int, int(2*offset)+1)
+
+	// sharpness is the sum of squares of the profile, which is greatest when black pixels share rows
+	sharpness := func(degrees float64) int {
+		for i := range profile {
+			profile[i] = 0
+		}
+
+		sin, cos := math.Sincos(degrees * math.Pi / 180)
+		for i := range xs {
+			profile[int(xs[i]*sin+ys[i]*cos+offset)]++
+		}
+
+		sum := 0
+		for _, v := range profile {
+			sum += v * v
+		}
+
+		return sum
+	}
+
+	search := func(from, to, step float64) float64 {
+		best, bestScore := 0.0, -1
+		for a := from; a <= to+step/2; a += step {
+			if score := sharpness(a); score > bestScore {
+				best, bestScore = a, score
+			}
+		}
+
+		return best
+	}
+
+	// a coarse search, then refine around the best candidate
+	coarse := search(-MaxSkewDegrees, MaxSkewDegrees, 0.5)
+	return search(coarse-0.5, coarse+0.5, 0.1)
+}
+
+// Deskew straightens src by undoing the rotation found by EstimateSkew. Areas uncovered by the rotation are
+// filled with the color of the top-left pixel.
+func Deskew(src image.Image) image.Image {
+	angle := EstimateSkew(src)
+	if math.Abs(angle) < 0.05 {
+		return src
+	}
+
+	return Rotate(src, -angle, src.At(src.Bounds().Min.X, src.Bounds().Min.Y))
+}
+
 // NoiseImage randomly alters the pixels of the given image.
 // Originally this used randomColor(), but that result in some black pixels, which totally defeats the
 // bounding box algorithm. A better BBox algorithm would be nice...
@@ -155,25 +310,25 @@ func ImageToString(img image.Image) (result string) {
 	return
 }
 
+// IsYAxisBlank returns true if column x of the black & white image src has no black pixels in it.
 func IsYAxisBlank(src image.Image, x int) bool {
-	max := src.Bounds().Max
-	for y := 0; y < max.Y; y++ {
-		c := src.At(x, y)
-		r, _, _, _ := c.RGBA()
-		if r == 0 {
-			fmt.Println(c, "false")
+	b := src.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		if IsBlack(src.At(x, y)) {
 			return false
 		}
 	}
 
-	fmt.Println("true")
 	return true
 }
 
+// ImageSegmentV finds the first run of non-blank columns in the black & white image src, at or to the right of
+// column x. It returns the first column of the run, and the column just past its end. If there are no more
+// non-blank columns, both are src.Bounds().Max.X.
 func ImageSegmentV(src image.Image, x int) (int, int) {
 	max := src.Bounds().Max
 
-	s := x
+	s := max.X
 	find := false
 	for ; x < max.X; x++ {
 		if !IsYAxisBlank(src, x) {
@@ -215,21 +370,49 @@ func NewSubRGBA(rgba image.Image, b image.Rectangle) *image.RGBA {
 	return result
 }
 
-func ImageSplit(rgba image.Image) []*image.RGBA {
-	var ret []*image.RGBA
-	start := 0
-	end := 0
-	for {
-		start, end = ImageSegmentV(rgba, start)
-
-		fmt.Println(start, end, rgba.Bounds().Max.X)
-		if end == rgba.Bounds().Max.X {
-			break
+// IsXAxisBlank returns true if row y of the black & white image src has no black pixels in it.
+func IsXAxisBlank(src image.Image, y int) bool {
+	b := src.Bounds()
+	for x := b.Min.X; x < b.Max.X; x++ {
+		if IsBlack(src.At(x, y)) {
+			return false
 		}
+	}
+
+	return true
+}
+
+// ImageSegmentH is like ImageSegmentV, but finds the first run of non-blank rows at or below row y. It returns
+// the first row of the run, and the row just past its end.
+func ImageSegmentH(src image.Image, y int) (int, int) {
+	max := src.Bounds().Max
+
+	s := max.Y
+	find := false
+	for ; y < max.Y; y++ {
+		if !IsXAxisBlank(src, y) {
+			if !find {
+				s = y
+				find = true
+			}
+		} else {
+			if find {
+				break
+			}
+		}
+	}
+
+	return s, y
+}
+
+// ImageSplit splits the black & white image src into vertical strips, one for each run of non-blank columns,
+// from left to right. Each strip is the full height of src.
+func ImageSplit(src image.Image) []*image.RGBA {
+	var ret []*image.RGBA
+	b := src.Bounds()
 
-		newRgba := NewSubRGBA(rgba, image.Rect(start, 0, end, rgba.Bounds().Max.Y))
-		start = end
-		ret = append(ret, newRgba)
+	for start, end := ImageSegmentV(src, b.Min.X); start < end; start, end = ImageSegmentV(src, end) {
+		ret = append(ret, NewSubRGBA(src, image.Rect(start, b.Min.Y, end, b.Max.Y)))
 	}
 
 	return ret
@@ -280,7 +463,6 @@ func ImageThreshold(gray *image.Gray, thresh uint8, ty int) {
 				v = 0
 			}
 
-			fmt.Println(v)
 			if ty == 1 {
 				v = 255 - v
 			}
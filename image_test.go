@@ -3,6 +3,8 @@ package gocarina
 import (
 	"image"
 	"image/color"
+	"image/draw"
+	"math"
 	"testing"
 )
 
@@ -68,3 +70,58 @@ func assertHeight(rect image.Rectangle, h int, t *testing.T) {
 		t.Fatalf("expected rect.Bounds().Dy() to be %d, was: %d", h, rect.Bounds().Dy())
 	}
 }
+
+func TestRotate(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+	img.Set(12, 7, color.Black)
+
+	// a quarter turn counter-clockwise moves the right-hand side to the top
+	rotated := Rotate(img, 90, color.White)
+	bbox := BoundingBox(rotated, 0)
+
+	if bbox != image.Rect(7, 3, 8, 4) {
+		t.Fatalf("expected pixel to move to %v, got: %v", image.Rect(7, 3, 8, 4), bbox)
+	}
+}
+
+func TestEstimateSkew(t *testing.T) {
+	img := readImage("board-images/board1.png")
+
+	for _, degrees := range []float64{0, 3, -2.5} {
+		skewed := Rotate(img, degrees, color.White)
+		actual := EstimateSkew(skewed)
+
+		if math.Abs(actual-degrees) > 0.3 {
+			t.Errorf("expected skew of about %.1f, got: %.1f", degrees, actual)
+		}
+	}
+}
+
+func TestImageSplit(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.ZP, draw.Src)
+
+	// three blocks, the last touching the right-hand edge
+	blocks := []image.Rectangle{image.Rect(2, 2, 5, 8), image.Rect(7, 3, 8, 9), image.Rect(15, 0, 20, 10)}
+	for _, r := range blocks {
+		draw.Draw(img, r, &image.Uniform{color.Black}, image.ZP, draw.Src)
+	}
+
+	strips := ImageSplit(img)
+	if len(strips) != len(blocks) {
+		t.Fatalf("expected %d strips, got: %d", len(blocks), len(strips))
+	}
+
+	for i, strip := range strips {
+		if strip.Bounds().Dx() != blocks[i].Dx() || strip.Bounds().Dy() != img.Bounds().Dy() {
+			t.Errorf("strip %d: expected %dx%d, got: %v", i, blocks[i].Dx(), img.Bounds().Dy(), strip.Bounds())
+		}
+	}
+
+	blank := image.NewRGBA(image.Rect(0, 0, 5, 5))
+	draw.Draw(blank, blank.Bounds(), &image.Uniform{color.White}, image.ZP, draw.Src)
+	if strips := ImageSplit(blank); len(strips) != 0 {
+		t.Errorf("expected no strips for a blank image, got: %d", len(strips))
+	}
+}
@@ -0,0 +1,137 @@
+package gocarina
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sync"
+)
+
+// MaxAspectSkewPercent is how far the aspect ratio of an image may differ from that of a layout's canvas
+// for the layout to be scaled to fit it.
+const MaxAspectSkewPercent = 0.005
+
+// BoardLayout describes the geometry of a Letterpress screenshot taken on a particular device.
+type BoardLayout struct {
+	Name   string      // e.g. "iphone5"
+	Grid               // where the tiles lie within the screenshot
+	Canvas image.Point // the expected width and height of the screenshot
+}
+
+// DefaultLayout is the geometry of the iPhone 5 screenshots in board-images/. It is used when an image matches
+// no registered layout, and no grid can be detected in it.
+var DefaultLayout = BoardLayout{
+	Name:   "iphone5",
+	Grid:   Grid{Origin: image.Pt(0, 496), TileSize: 128, Rows: 5, Cols: 5},
+	Canvas: image.Pt(640, 1136),
+}
+
+// Only the layout of the iPhone 5 is built in, as it's the only one measured from real screenshots. Layouts for other
+// devices can be added with RegisterLayout.
+var (
+	layoutsMu sync.RWMutex
+	layouts   = []BoardLayout{DefaultLayout}
+)
+
+// RegisterLayout adds a layout to the registry consulted by LayoutFor, replacing any existing layout with the same
+// name. Layouts registered later take precedence over earlier ones with the same canvas size.
+func RegisterLayout(l BoardLayout) {
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+
+	for i, existing := range layouts {
+		if existing.Name == l.Name {
+			layouts = append(layouts[:i], layouts[i+1:]...)
+			break
+		}
+	}
+
+	layouts = append(layouts, l)
+}
+
+// Layouts returns the registered layouts, in order of registration.
+func Layouts() []BoardLayout {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+
+	return append([]BoardLayout(nil), layouts...)
+}
+
+// LayoutFor returns the layout for a screenshot of the given size. A layout whose canvas is exactly that size is
+// preferred; failing that, a layout whose canvas has the same aspect ratio is scaled to fit.
+func LayoutFor(size image.Point) (BoardLayout, bool) {
+	return layoutFor(size, nil)
+}
+
+// layoutFor is like LayoutFor, but only considers layouts for which match returns true. A nil match accepts all.
+func layoutFor(size image.Point, match func(BoardLayout) bool) (BoardLayout, bool) {
+	var candidates []BoardLayout
+	for _, l := range Layouts() {
+		if match == nil || match(l) {
+			candidates = append(candidates, l)
+		}
+	}
+
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if candidates[i].Canvas == size {
+			return candidates[i], true
+		}
+	}
+
+	if size.X <= 0 || size.Y <= 0 {
+		return BoardLayout{}, false
+	}
+
+	aspect := float64(size.X) / float64(size.Y)
+	best, bestSkew := -1, 0.0
+
+	for i := len(candidates) - 1; i >= 0; i-- {
+		c := candidates[i].Canvas
+		skew := math.Abs(float64(c.X)/float64(c.Y)-aspect) / aspect
+
+		if skew <= MaxAspectSkewPercent && (best < 0 || skew < bestSkew) {
+			best, bestSkew = i, skew
+		}
+	}
+
+	if best < 0 {
+		return BoardLayout{}, false
+	}
+
+	return candidates[best].ScaledTo(size), true
+}
+
+// ScaledTo returns a copy of the layout, with its geometry scaled to fit a canvas of the given size.
+func (l BoardLayout) ScaledTo(size image.Point) BoardLayout {
+	if size == l.Canvas {
+		return l
+	}
+
+	scale := float64(size.X) / float64(l.Canvas.X)
+	round := func(v int) int {
+		return int(math.Floor(float64(v)*scale + 0.5))
+	}
+
+	l.Origin = image.Pt(round(l.Origin.X), round(l.Origin.Y))
+	l.TileSize = round(l.TileSize)
+	l.Canvas = size
+
+	return l
+}
+
+// Resized returns a copy of the layout with a grid of rows x cols tiles in place of its own. The new grid keeps the
+// width and bottom edge of the old one, as Letterpress grids span the full width at the bottom of the screen.
+func (l BoardLayout) Resized(rows, cols int) BoardLayout {
+	if rows == l.Rows && cols == l.Cols {
+		return l
+	}
+
+	bounds := l.Bounds()
+
+	l.Name = fmt.Sprintf("%s-%dx%d", l.Name, cols, rows)
+	l.TileSize = bounds.Dx() / cols
+	l.Origin = image.Pt(bounds.Min.X, bounds.Max.Y-rows*l.TileSize)
+	l.Rows, l.Cols = rows, cols
+
+	return l
+}
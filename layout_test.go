@@ -0,0 +1,87 @@
+package gocarina
+
+import (
+	"image"
+	"image/draw"
+	"os"
+	"testing"
+)
+
+func TestLayoutFor(t *testing.T) {
+	var examples = []struct {
+		size image.Point
+		ok   bool
+		name string
+		grid Grid
+	}{
+		{image.Pt(640, 1136), true, "iphone5", Grid{image.Pt(0, 496), 128, 5, 5}},
+
+		// a larger screenshot of nearly the same aspect ratio, scaled from the iPhone 5 layout
+		{image.Pt(750, 1334), true, "iphone5", Grid{image.Pt(0, 581), 150, 5, 5}},
+
+		// a downsized iPhone 5 screenshot
+		{image.Pt(320, 568), true, "iphone5", Grid{image.Pt(0, 248), 64, 5, 5}},
+
+		{image.Pt(1000, 1000), false, "", Grid{}},
+	}
+
+	for _, tt := range examples {
+		layout, ok := LayoutFor(tt.size)
+
+		if ok != tt.ok {
+			t.Errorf("%v: expected ok to be %t", tt.size, tt.ok)
+			continue
+		}
+
+		if layout.Name != tt.name || layout.Grid != tt.grid {
+			t.Errorf("%v: expected %s layout with grid %v, got: %s with %v", tt.size, tt.name, tt.grid, layout.Name, layout.Grid)
+		}
+
+		if ok && layout.Canvas != tt.size {
+			t.Errorf("%v: expected canvas to be scaled to fit, got: %v", tt.size, layout.Canvas)
+		}
+	}
+}
+
+func TestRegisterLayout(t *testing.T) {
+	saved := Layouts()
+	defer func() { layouts = saved }()
+
+	// a device that shows the grid in the middle of the screen
+	RegisterLayout(BoardLayout{
+		Name:   "custom",
+		Grid:   Grid{Origin: image.Pt(130, 300), TileSize: 128, Rows: 5, Cols: 5},
+		Canvas: image.Pt(900, 1400),
+	})
+
+	img := readImage("board-images/board1.png")
+	screenshot := image.NewRGBA(image.Rect(0, 0, 900, 1400))
+	draw.Draw(screenshot, screenshot.Bounds(), &image.Uniform{BackgroundColor(img)}, image.ZP, draw.Src)
+	draw.Draw(screenshot, image.Rect(130, 300, 770, 940), img, image.Pt(0, 496), draw.Src)
+
+	// add a large distraction below the grid, which would fool DetectGrid
+	draw.Draw(screenshot, image.Rect(0, 960, 900, 1400), image.Black, image.ZP, draw.Src)
+
+	file := writeTempPNG(screenshot, t)
+	defer os.Remove(file)
+
+	b := ReadUnknownBoard(file)
+	if b.Layout != "custom" {
+		t.Fatalf("expected custom layout, got: %q", b.Layout)
+	}
+
+	assertSimilarTiles(ReadUnknownBoard("board-images/board1.png"), b, 0, t)
+}
+
+func TestLayoutResized(t *testing.T) {
+	l := DefaultLayout.Resized(4, 4)
+
+	expected := Grid{image.Pt(0, 496), 160, 4, 4}
+	if l.Grid != expected {
+		t.Errorf("expected %v, got: %v", expected, l.Grid)
+	}
+
+	if l.Bounds().Max != DefaultLayout.Bounds().Max {
+		t.Errorf("expected the grid to keep its bottom-right corner at %v, got: %v", DefaultLayout.Bounds().Max, l.Bounds().Max)
+	}
+}
@@ -6,41 +6,170 @@ import (
 	"log"
 	"os"
 	"strings"
+	"unicode"
 )
 
-// describes the geometry of the letterpress board source images
-const (
-	LetterpressTilesAcross    = 5
-	LetterpressTilesDown      = 5
-	LetterpressTilePixels     = 128
-	LetterpressHeightOffset   = 496
-	LetterPressExpectedWidth  = LetterpressTilesAcross * LetterpressTilePixels
-	LetterpressExpectedHeight = 1136
-)
+// Alphabet is the letters that appear on the tiles of a Letterpress board.
+const Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
 // Board represents a Letterpress game board
 type Board struct {
-	img   image.Image
-	Tiles []*Tile
+	img    image.Image
+	Grid   Grid   // where the tiles were found in the board image
+	Layout string // name of the BoardLayout that matched the image, or "" if the grid was detected
+	Tiles  []*Tile
 }
 
-// ReadKnownBoard reads the given file into an image, and assigns letters to the board tiles.
+// BoardOption customizes how a board image is read.
+type BoardOption func(*boardOptions)
+
+type boardOptions struct {
+	deskew     bool
+	rectify    bool
+	rows, cols int // 0 if any grid size is acceptable
+}
+
+// WithDeskew straightens the board image before it is sliced into tiles. This helps with photos of a phone
+// screen, which are rarely perfectly upright.
+func WithDeskew() BoardOption {
+	return func(o *boardOptions) {
+		o.deskew = true
+	}
+}
+
+// WithRectify corrects the perspective of a camera image of a board (see Rectify) before it is sliced into tiles.
+func WithRectify() BoardOption {
+	return func(o *boardOptions) {
+		o.rectify = true
+	}
+}
+
+// WithGridSize reads a board of rows x cols tiles, e.g. 4x4 for Boggle, instead of the 5x5 Letterpress grid.
+// Only layouts of that size are considered, and a grid of that size is looked for if none match.
+func WithGridSize(rows, cols int) BoardOption {
+	return func(o *boardOptions) {
+		o.rows, o.cols = rows, cols
+	}
+}
+
+// ReadKnownBoard reads the given file into an image, and assigns letters to the board tiles, in row-major order.
 // The returned Board can be used for training a network.
-func ReadKnownBoard(file string, letters []rune) *Board {
-	return readBoard(file, letters)
+func ReadKnownBoard(file string, letters []rune, opts ...BoardOption) *Board {
+	return readBoard(file, letters, opts)
 }
 
 // ReadUnknownBoard reads the given file into an image, and assigns ? characters to the board tiles.
-// The tiles from the returned board can then be sent through a (pre-trained) network to be recognized.
-func ReadUnknownBoard(file string) *Board {
-	letters := []rune(strings.Repeat("?", 25))
-	return readBoard(file, letters)
+// The tiles from the returned board can then be sent through a (pre-trained) network to be recognized, e.g. with
+// Board.Recognize.
+func ReadUnknownBoard(file string, opts ...BoardOption) *Board {
+	return readBoard(file, nil, opts)
+}
+
+// NewBoard returns a board of rows x cols tiles that wasn't read from an image, e.g. one entered by hand. letters are
+// those of the tiles in row-major order, and owners their owners: b or r for blue or red, in upper case if locked,
+// and anything else if unowned.
+func NewBoard(rows, cols int, letters, owners string) *Board {
+	b := &Board{Grid: Grid{Rows: rows, Cols: cols}}
+
+	o := []rune(owners)
+	for i, letter := range []rune(letters) {
+		tile := &Tile{Letter: letter}
+
+		switch unicode.ToLower(o[i]) {
+		case 'b':
+			tile.Owner = Blue
+		case 'r':
+			tile.Owner = Red
+		}
+		tile.Locked = unicode.IsUpper(o[i])
+
+		b.Tiles = append(b.Tiles, tile)
+	}
+
+	return b
+}
+
+// Rows returns the number of tiles down the board.
+func (b *Board) Rows() int {
+	return b.Grid.Rows
+}
+
+// Cols returns the number of tiles across the board.
+func (b *Board) Cols() int {
+	return b.Grid.Cols
+}
+
+// Tile returns the tile at the given row and column, or nil if there is no such tile.
+func (b *Board) Tile(row, col int) *Tile {
+	if row < 0 || row >= b.Rows() || col < 0 || col >= b.Cols() {
+		return nil
+	}
+
+	return b.Tiles[b.Index(row, col)]
+}
+
+// Index returns the index into b.Tiles of the tile at the given row and column.
+func (b *Board) Index(row, col int) int {
+	return row*b.Cols() + col
 }
 
-func readBoard(file string, letters []rune) *Board {
+// Position returns the row and column of the tile at the given index into b.Tiles.
+func (b *Board) Position(i int) (row, col int) {
+	return i / b.Cols(), i % b.Cols()
+}
+
+// Recognize runs each tile of the board through the network, which must have been trained on the letters of the
+// Alphabet. Tiles the network is less than minConfidence sure of are left as Wildcards, with its top best guesses
+// as their Candidates (or none, if top is 0, in which case they may stand for any letter at all).
+func (b *Board) Recognize(n *Network, minConfidence float64, top int) {
+	for _, tile := range b.Tiles {
+		candidates := n.TileCandidates(tile, Alphabet, 0)
+
+		tile.Letter, tile.Candidates = candidates[0].Char, nil
+		if candidates[0].Confidence >= minConfidence {
+			continue
+		}
+
+		tile.Letter = Wildcard
+
+		for i := 0; i < top && i < len(candidates); i++ {
+			tile.Candidates = append(tile.Candidates, candidates[i].Char)
+		}
+	}
+}
+
+// if letters is nil, Wildcard characters are assigned to the tiles, however many there turn out to be
+func readBoard(file string, letters []rune, opts []BoardOption) *Board {
+	var o boardOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	b := &Board{}
 	b.img = readImage(file)
-	images := b.scaleAndCrop()
+
+	if o.deskew {
+		b.img = Deskew(b.img)
+	}
+
+	if o.rectify {
+		img, err := Rectify(b.img)
+		if err != nil {
+			log.Fatal(err)
+		}
+		b.img = img
+	}
+
+	images := b.detectAndCrop(o.rows, o.cols)
+
+	if letters == nil {
+		letters = []rune(strings.Repeat(string(Wildcard), len(images)))
+	}
+
+	if len(letters) != len(images) {
+		log.Fatalf("expected %d letters for a %dx%d board, got: %d", len(images), b.Cols(), b.Rows(), len(letters))
+	}
+
 	for i, img := range images {
 		tile := NewTile(letters[i], img)
 		b.Tiles = append(b.Tiles, tile)
@@ -109,32 +238,49 @@ func readImage(file string) image.Image {
 	return img
 }
 
-// crops a letterpress screen grab into a slice of tile images, one per letter.
-func (b *Board) scaleAndCrop() (result []image.Image) {
-	if b.img.Bounds().Dx() != LetterPressExpectedWidth || b.img.Bounds().Dy() != LetterpressExpectedHeight {
+// crops a letterpress screen grab into a slice of tile images, one per letter, in row-major order. The grid is taken
+// from the registered layout for the image size if there is one, or else detected in the image. As a last resort,
+// the image is scaled to fit the DefaultLayout. If rows and cols are non-zero, the grid must be of that size.
+func (b *Board) detectAndCrop(rows, cols int) (result []image.Image) {
+	size := b.img.Bounds().Size()
+
+	matches := func(l BoardLayout) bool {
+		return rows == 0 || (l.Rows == rows && l.Cols == cols)
+	}
+
+	if rows == 0 {
+		rows, cols = DefaultLayout.Rows, DefaultLayout.Cols
+	}
+
+	if layout, ok := layoutFor(size, matches); ok {
+		// the layout applies relative to the image's own origin
+		b.Grid = layout.Grid
+		b.Grid.Origin = b.Grid.Origin.Add(b.img.Bounds().Min)
+		b.Layout = layout.Name
+	} else if grid, err := DetectGrid(b.img, rows, cols); err == nil {
+		b.Grid = grid
+	} else {
+		layout := DefaultLayout.Resized(rows, cols)
+		log.Printf("%s, assuming %s layout", err, layout.Name)
 		log.Printf("Scaling...\n")
-		b.img = Scale(b.img, image.Rect(0, 0, LetterPressExpectedWidth, LetterpressExpectedHeight))
+
+		b.img = Scale(b.img, image.Rectangle{Max: layout.Canvas})
+		b.Grid = layout.Grid
+		b.Layout = layout.Name
 	}
 
-	yOffset := LetterpressHeightOffset
 	border := 1
 
-	for i := 0; i < LetterpressTilesDown; i++ {
-		xOffset := 0
-
-		for j := 0; j < LetterpressTilesAcross; j++ {
-			tileRect := image.Rect(xOffset+border, yOffset+border, xOffset+LetterpressTilePixels-border, yOffset+LetterpressTilePixels-border)
+	for row := 0; row < b.Grid.Rows; row++ {
+		for col := 0; col < b.Grid.Cols; col++ {
+			tileRect := b.Grid.TileRect(row, col).Inset(border)
 
 			tile := b.img.(interface {
 				SubImage(r image.Rectangle) image.Image
 			}).SubImage(tileRect)
 
 			result = append(result, tile)
-
-			xOffset += LetterpressTilePixels
 		}
-
-		yOffset += LetterpressTilePixels
 	}
 
 	return
@@ -3,7 +3,9 @@ package gocarina
 import (
 	"fmt"
 	"image"
+	"image/draw"
 	"image/png"
+	"io/ioutil"
 	"os"
 	"testing"
 )
@@ -56,3 +58,172 @@ func TestNoise(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestReadUnknownBoardWithDeskew(t *testing.T) {
+	// a skewed copy of a known board
+	img := readImage("board-images/board1.png")
+	file := writeTempPNG(Rotate(img, 4, img.At(0, 0)), t)
+	defer os.Remove(file)
+
+	// the straightened tiles should bound to roughly the same glyphs as the originals
+	straight := ReadUnknownBoard("board-images/board1.png")
+	assertSimilarTiles(straight, ReadUnknownBoard(file, WithDeskew()), 3, t)
+}
+
+func TestReadUnknownBoardWithRectify(t *testing.T) {
+	img := readImage("board-images/board1.png")
+	bg := BackgroundColor(img)
+
+	// project the grid onto a keystoned quadrilateral, as a camera held at an angle would
+	quad := [4]point{{60, 220}, {700, 180}, {760, 950}, {20, 900}}
+	grid := [4]point{{0, 496}, {640, 496}, {640, 1136}, {0, 1136}}
+
+	h, err := solveHomography(quad, grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	photo := image.NewRGBA(image.Rect(0, 0, 800, 1000))
+	for y := 0; y < 1000; y++ {
+		for x := 0; x < 800; x++ {
+			sx, sy := h.apply(float64(x)+0.5, float64(y)+0.5)
+			if sx >= 0 && sx < 640 && sy >= 496 && sy < 1136 {
+				photo.Set(x, y, img.At(int(sx), int(sy)))
+			} else {
+				photo.Set(x, y, bg)
+			}
+		}
+	}
+
+	file := writeTempPNG(photo, t)
+	defer os.Remove(file)
+
+	straight := ReadUnknownBoard("board-images/board1.png")
+	assertSimilarTiles(straight, ReadUnknownBoard(file, WithRectify()), 3, t)
+}
+
+func TestRectifyNoGrid(t *testing.T) {
+	blank := image.NewRGBA(image.Rect(0, 0, 64, 64))
+
+	if _, err := Rectify(blank); err == nil {
+		t.Fatalf("expected an error for an image without a grid")
+	}
+}
+
+func writeTempPNG(img image.Image, t *testing.T) string {
+	f, err := ioutil.TempFile("", "board*.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err = png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return f.Name()
+}
+
+// assertSimilarTiles checks that the tiles of actual bound to roughly the same glyphs as those of expected.
+func assertSimilarTiles(expected, actual *Board, tolerance int, t *testing.T) {
+	if len(actual.Tiles) != len(expected.Tiles) {
+		t.Fatalf("expected %d tiles, got: %d", len(expected.Tiles), len(actual.Tiles))
+	}
+
+	for i, tile := range actual.Tiles {
+		e := expected.Tiles[i].Bounded.Bounds()
+		a := tile.Bounded.Bounds()
+
+		if abs(e.Dx()-a.Dx()) > tolerance || abs(e.Dy()-a.Dy()) > tolerance {
+			t.Errorf("tile %d: expected bounds close to %v, got: %v", i, e, a)
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+func TestReadUnknownBoardGridSizes(t *testing.T) {
+	img := readImage("board-images/board1.png")
+	original := ReadUnknownBoard("board-images/board1.png")
+
+	var examples = []struct {
+		rows, cols int
+	}{
+		{4, 4}, // Boggle
+		{3, 5}, // something crossword-like
+	}
+
+	for _, tt := range examples {
+		// lift the top-left corner of the Letterpress grid onto a fresh canvas
+		screenshot := image.NewRGBA(image.Rect(0, 0, 800, 900))
+		draw.Draw(screenshot, screenshot.Bounds(), &image.Uniform{BackgroundColor(img)}, image.ZP, draw.Src)
+		draw.Draw(screenshot, image.Rect(50, 100, 50+tt.cols*128, 100+tt.rows*128), img, image.Pt(0, 496), draw.Src)
+
+		file := writeTempPNG(screenshot, t)
+		defer os.Remove(file)
+
+		b := ReadUnknownBoard(file, WithGridSize(tt.rows, tt.cols))
+
+		if b.Rows() != tt.rows || b.Cols() != tt.cols || len(b.Tiles) != tt.rows*tt.cols {
+			t.Errorf("expected %dx%d board, got %dx%d with %d tiles", tt.cols, tt.rows, b.Cols(), b.Rows(), len(b.Tiles))
+			continue
+		}
+
+		for row := 0; row < tt.rows; row++ {
+			for col := 0; col < tt.cols; col++ {
+				expected := original.Tile(row, col).Bounded.Bounds()
+				actual := b.Tile(row, col).Bounded.Bounds()
+
+				if expected.Size() != actual.Size() {
+					t.Errorf("%dx%d tile (%d, %d): expected bounds like %v, got: %v", tt.cols, tt.rows, row, col, expected, actual)
+				}
+
+				if r, c := b.Position(b.Index(row, col)); r != row || c != col {
+					t.Errorf("expected position (%d, %d), got: (%d, %d)", row, col, r, c)
+				}
+			}
+		}
+
+		if b.Tile(tt.rows, 0) != nil || b.Tile(0, -1) != nil {
+			t.Errorf("expected nil for tiles outside the grid")
+		}
+	}
+}
+
+func TestBoardRecognize(t *testing.T) {
+	n := NewNetwork(TileTargetWidth, TileTargetHeight)
+	for _, tile := range ReadKnownBoards() {
+		n.TrainTile(tile)
+	}
+
+	b := ReadUnknownBoard("board-images/board3.png")
+	b.Recognize(n, 0, 0)
+
+	// training doesn't reliably converge (see README), so rather than the letters themselves, check that each tile
+	// was given the network's best guess, and that the guesses become candidates when they aren't good enough
+	var best []rune
+	for i, tile := range b.Tiles {
+		candidates := n.TileCandidates(tile, Alphabet, 3)
+		best = append(best, candidates[0].Char)
+
+		if tile.Letter != candidates[0].Char || tile.Candidates != nil {
+			t.Errorf("tile %d: expected %c, got: %c %q", i, candidates[0].Char, tile.Letter, string(tile.Candidates))
+		}
+	}
+
+	// nothing is recognized with more than complete confidence, so every tile becomes a wildcard
+	b.Recognize(n, 1.1, 3)
+
+	for i, tile := range b.Tiles {
+		if tile.Letter != Wildcard || len(tile.Candidates) != 3 || tile.Candidates[0] != best[i] {
+			t.Errorf("tile %d: expected a wildcard with 3 candidates, starting with %c, got: %c %q",
+				i, best[i], tile.Letter, string(tile.Candidates))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package gocarina
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+const (
+	SpaceGapPercent = 0.3 // gaps between glyphs at least this fraction of the line's height are taken as spaces
+	SpaceGapRatio   = 2.0 // word gaps are expected to be at least this many times wider than gaps between letters
+)
+
+// RecognizeLine reads a single line of text from img, which may be dark text on a light background or the reverse.
+// The line is split into glyphs at the blank columns between them (see ImageSplit), and each glyph is recognized by
+// the network as if it were a tile. Glyphs that touch are cut apart, and glyphs broken in two are rejoined,
+// wherever the network is more confident of the result. Gaps between glyphs that are wide compared to the others,
+// or to the height of the text, are read as spaces.
+func RecognizeLine(n *Network, img image.Image) (string, error) {
+	result, err := recognizeLineResult(n, img, false)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Text, nil
+}
+
+// lineGlyphs returns the bounds of the ink of each glyph in the black & white image bw, from left to right.
+func lineGlyphs(bw image.Image) []image.Rectangle {
+	var result []image.Rectangle
+	b := bw.Bounds()
+
+	for start, end := ImageSegmentV(bw, b.Min.X); start < end; start, end = ImageSegmentV(bw, end) {
+		result = append(result, inkBounds(bw, image.Rect(start, b.Min.Y, end, b.Max.Y)))
+	}
+
+	return result
+}
+
+// spaceThreshold returns the narrowest gap between glyphs that is read as a space. If the gaps fall into two
+// distinct groups, narrow and wide, the threshold lies between them. Otherwise they are all gaps between letters,
+// or all spaces, depending on how they compare with the height of the line.
+func spaceThreshold(gaps []int, height int) float64 {
+	minSpace := SpaceGapPercent * float64(height)
+	if len(gaps) < 2 {
+		return minSpace
+	}
+
+	sorted := append([]int(nil), gaps...)
+	sort.Ints(sorted)
+
+	// split at the widest jump between consecutive gap widths
+	split := 0
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i]-sorted[i-1] > sorted[split+1]-sorted[split] {
+			split = i - 1
+		}
+	}
+
+	narrow, wide := float64(sorted[split]), float64(sorted[split+1])
+	if wide >= SpaceGapRatio*narrow && wide >= minSpace/SpaceGapRatio {
+		return (narrow + wide) / 2
+	}
+
+	return minSpace
+}
+
+// glyphImage returns a black & white image of the part of bw within r, with a one pixel white margin.
+func glyphImage(bw image.Image, r image.Rectangle) image.Image {
+	img := image.NewGray(r.Inset(-1))
+
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			if image.Pt(x, y).In(r) && IsBlack(bw.At(x, y)) {
+				img.SetGray(x, y, color.Gray{0})
+			} else {
+				img.SetGray(x, y, color.Gray{0xff})
+			}
+		}
+	}
+
+	return img
+}
@@ -0,0 +1,120 @@
+package gocarina
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestSpaceThreshold(t *testing.T) {
+	examples := []struct {
+		gaps   []int
+		height int
+		spaces []bool
+	}{
+		{[]int{2, 3, 2, 9, 2, 3}, 20, []bool{false, false, false, true, false, false}},
+		{[]int{2, 3, 2, 2}, 20, []bool{false, false, false, false}},
+		{[]int{8}, 20, []bool{true}},
+		{[]int{2}, 20, []bool{false}},
+		{[]int{9, 10, 9}, 20, []bool{true, true, true}},
+	}
+
+	for _, ex := range examples {
+		threshold := spaceThreshold(ex.gaps, ex.height)
+		for i, gap := range ex.gaps {
+			if got := float64(gap) >= threshold; got != ex.spaces[i] {
+				t.Errorf("gaps %v: expected gap %d space to be %t, got: %t", ex.gaps, i, ex.spaces[i], got)
+			}
+		}
+	}
+}
+
+// drawText sets the glyphs from the known tiles out as lines of text, light on dark, returning the image and the
+// glyphs of each line.
+func drawText(tiles map[rune]*Tile, lines ...string) (*image.RGBA, [][]image.Image) {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 130*len(lines)))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.Black}, image.ZP, draw.Src)
+
+	var result [][]image.Image
+	for i, text := range lines {
+		var glyphs []image.Image
+
+		x := 10
+		for _, c := range text {
+			if c == ' ' {
+				x += 40
+				continue
+			}
+
+			src := NormalizePolarity(tiles[c].img)
+			minArea := int(SpeckAreaPercent * float64(src.Bounds().Dx()*src.Bounds().Dy()))
+			glyph := glyphImage(src, GlyphBoundingBox(src, minArea, 0))
+			glyphs = append(glyphs, glyph)
+
+			g := glyph.Bounds()
+			for y := g.Min.Y; y < g.Max.Y; y++ {
+				for gx := g.Min.X; gx < g.Max.X; gx++ {
+					if IsBlack(glyph.At(gx, y)) {
+						img.Set(x+gx-g.Min.X, 130*i+10+y-g.Min.Y, color.White)
+					}
+				}
+			}
+			x += g.Dx() + 4
+		}
+
+		result = append(result, glyphs)
+	}
+
+	return img, result
+}
+
+// expectedText is what the network should read the text drawn by drawText as: training doesn't reliably converge
+// (see README), so it is whatever the network reads each glyph as.
+func expectedText(n *Network, lines []string, glyphs [][]image.Image) string {
+	var result []rune
+	for i, text := range lines {
+		if i > 0 {
+			result = append(result, '\n')
+		}
+
+		j := 0
+		for _, c := range text {
+			if c == ' ' {
+				result = append(result, ' ')
+				continue
+			}
+
+			result = append(result, n.RecognizeTile(NewTile(0, glyphs[i][j])))
+			j++
+		}
+	}
+
+	return string(result)
+}
+
+func TestRecognizeLine(t *testing.T) {
+	tiles := ReadKnownBoards()
+
+	n := NewNetwork(TileTargetWidth, TileTargetHeight)
+	for _, tile := range tiles {
+		n.TrainTile(tile)
+	}
+
+	lines := []string{"CAT SAT"}
+	img, glyphs := drawText(tiles, lines...)
+
+	got, err := RecognizeLine(n, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := expectedText(n, lines, glyphs); got != expected {
+		t.Errorf("expected %q, got: %q", expected, got)
+	}
+
+	blank := image.NewRGBA(image.Rect(0, 0, 50, 20))
+	if _, err := RecognizeLine(n, blank); err == nil {
+		t.Errorf("expected an error for a blank line")
+	}
+}
@@ -11,14 +11,16 @@ import (
 	"log"
 	"math"
 	"math/rand"
+	"sort"
 	"strconv"
 	"time"
 )
 
 const (
-	NumOutputs            = 8    // number of output bits. This constrains the range of chars that are recognizable.
-	MinBoundingBoxPercent = 0.25 // threshold width for imposing a bounding box on char width/height
-	TileTargetWidth       = 12   // tiles get scaled down to these dimensions
+	NumOutputs            = 8      // number of output bits. This constrains the range of chars that are recognizable.
+	MinBoundingBoxPercent = 0.25   // threshold width for imposing a bounding box on char width/height
+	SpeckAreaPercent      = 0.0025 // components smaller than this fraction of a tile's area are ignored as noise
+	TileTargetWidth       = 12     // tiles get scaled down to these dimensions
 	TileTargetHeight      = 12
 )
 
@@ -42,8 +44,11 @@ type Network struct {
 	OutputErrors  []float64   // error from the output nodes
 	HiddenErrors  []float64   // error from the hidden nodes
 
-	tileWidth  int
-	tileHeight int
+	// how tiles are fitted to the inputs; tiles must be normalized this way for both training and recognition
+	Normalization Normalization
+
+	TileWidth  int // the size of the images fed to the network, one input per pixel
+	TileHeight int
 }
 
 // NewNetwork returns a new instance of a neural network, with the given number of input nodes.
@@ -55,8 +60,8 @@ func NewNetwork(w int, h int) *Network {
 		NumInputs:   numInputs,
 		HiddenCount: hiddenCount,
 		NumOutputs:  NumOutputs,
-		tileWidth:   w,
-		tileHeight:  h,
+		TileWidth:   w,
+		TileHeight:  h,
 	}
 
 	n.InputValues = make([]uint8, n.NumInputs)
@@ -90,6 +95,83 @@ func (n *Network) Train(img image.Image, r rune) {
 	n.adjustInputWeights()
 }
 
+// TrainTile trains the network on the given tile, first normalizing it to match the network.
+func (n *Network) TrainTile(t *Tile) {
+	t.Normalize(n.Normalization)
+	n.Train(t.Reduced, t.Letter)
+}
+
+// RecognizeTile attempts to recognize the letter on the given tile, first normalizing it to match the network.
+func (n *Network) RecognizeTile(t *Tile) rune {
+	t.Normalize(n.Normalization)
+	return n.Recognize(t.Reduced)
+}
+
+// Candidate is a character that the network may have recognized, and its confidence in it, from 0 to 1.
+type Candidate struct {
+	Char       rune
+	Confidence float64
+}
+
+// Candidates returns the top chars that the network is most confident are displayed on img, best first. Each output
+// of the network is taken as the probability that its bit is set, so the confidence in a char is the probability
+// of all of its bits together.
+func (n *Network) Candidates(img image.Image, chars string, top int) []Candidate {
+	n.assignInputs(img)
+	n.calculateHiddenOutputs()
+	n.calculateFinalOutputs()
+
+	var result []Candidate
+	for _, c := range chars {
+		confidence := 1.0
+		for i, bit := range n.runeToArrayOfInts(c) {
+			if bit == 1 {
+				confidence *= n.OutputValues[i]
+			} else {
+				confidence *= 1 - n.OutputValues[i]
+			}
+		}
+
+		result = append(result, Candidate{Char: c, Confidence: confidence})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Confidence > result[j].Confidence })
+
+	if top > 0 && len(result) > top {
+		result = result[:top]
+	}
+
+	return result
+}
+
+// TileCandidates is like Candidates, but for a tile, which is first normalized to match the network.
+func (n *Network) TileCandidates(t *Tile, chars string, top int) []Candidate {
+	t.Normalize(n.Normalization)
+	return n.Candidates(t.Reduced, chars, top)
+}
+
+// BestCandidate returns the character the network recognizes on img, as for Recognize, along with its confidence
+// in it, as for Candidates. No other character has a higher confidence.
+func (n *Network) BestCandidate(img image.Image) Candidate {
+	n.assignInputs(img)
+	n.calculateHiddenOutputs()
+	n.calculateFinalOutputs()
+
+	var c rune
+	confidence := 1.0
+	for _, v := range n.OutputValues {
+		c <<= 1
+		if round(v) == 1 {
+			c |= 1
+			confidence *= v
+		} else {
+			confidence *= 1 - v
+		}
+	}
+
+	return Candidate{Char: c, Confidence: confidence}
+}
+
 // Attempt to recognize the character displayed on the given image.
 func (n *Network) Recognize(img image.Image) rune {
 	n.assignInputs(img)
@@ -108,7 +190,7 @@ func (n *Network) Recognize(img image.Image) rune {
 		log.Fatalf("error in ParseInt for %s: ", err)
 	}
 
-	log.Printf("returning bitstring: %s", bitstring)
+	//log.Printf("returning bitstring: %s", bitstring)
 	return rune(asciiCode)
 }
 
@@ -143,6 +225,11 @@ func RestoreNetwork(filePath string) (*Network, error) {
 		return nil, fmt.Errorf("error decoding network: %s", err)
 	}
 
+	// networks saved without their tile size were trained on tiles of the target size
+	if result.TileWidth == 0 && result.TileHeight == 0 {
+		result.TileWidth, result.TileHeight = TileTargetWidth, TileTargetHeight
+	}
+
 	return &result, nil
 }
 
@@ -156,18 +243,18 @@ func round(f float64) int {
 
 // feed the image into the network
 func (n *Network) assignInputs(img image.Image) {
-	if img.Bounds().Dx() > n.tileWidth || img.Bounds().Dy() > n.tileHeight {
+	if img.Bounds().Dx() > n.TileWidth || img.Bounds().Dy() > n.TileHeight {
 		log.Fatalf("expected %d %d inputs, got %d %d",
-			n.tileWidth,
-			n.tileHeight,
+			n.TileWidth,
+			n.TileHeight,
 			img.Bounds().Dx(),
 			img.Bounds().Dy())
 	}
 	//log.Printf("numPixels: %d", numPixels)
 
 	i := 0
-	for row := img.Bounds().Min.Y; row < img.Bounds().Min.Y + n.tileHeight; row++ {
-		for col := img.Bounds().Min.X; col < img.Bounds().Min.X + n.tileWidth; col++ {
+	for row := img.Bounds().Min.Y; row < img.Bounds().Min.Y + n.TileHeight; row++ {
+		for col := img.Bounds().Min.X; col < img.Bounds().Min.X + n.TileWidth; col++ {
 			pixel := pixelToBit(img.At(col, row))
 			n.InputValues[i] = pixel
 			i++
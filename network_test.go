@@ -1,13 +1,15 @@
 package gocarina
 
 import (
+	"image"
 	"io/ioutil"
+	"math"
 	"reflect"
 	"testing"
 )
 
 func TestNetwork(t *testing.T) {
-	n := NewNetwork(25 * 25)
+	n := NewNetwork(25, 25)
 	n.calculateHiddenOutputs()
 	n.calculateOutputErrors('A')
 	n.calculateFinalOutputs()
@@ -17,7 +19,8 @@ func TestNetwork(t *testing.T) {
 }
 
 func TestSaveRestore(t *testing.T) {
-	n := NewNetwork(25 * 25)
+	n := NewNetwork(25, 25)
+	n.Normalization = NormalizeAspect
 	n.assignRandomWeights()
 
 	f, err := ioutil.TempFile("", "network")
@@ -31,8 +34,24 @@ func TestSaveRestore(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if !reflect.DeepEqual(n, restored) {
-		t.Fatalf("expected: %+v, got %+v", n, restored)
+	if restored.Normalization != n.Normalization {
+		t.Fatalf("expected normalization %d, got %d", n.Normalization, restored.Normalization)
+	}
+
+	if restored.String() != n.String() {
+		t.Fatalf("expected: %s, got %s", n, restored)
+	}
+
+	if restored.TileWidth != 25 || restored.TileHeight != 25 {
+		t.Errorf("expected 25x25 tiles, got: %dx%d", restored.TileWidth, restored.TileHeight)
+	}
+
+	if !reflect.DeepEqual(n.InputWeights, restored.InputWeights) {
+		t.Errorf("expected input weights to be restored")
+	}
+
+	if !reflect.DeepEqual(n.OutputWeights, restored.OutputWeights) {
+		t.Errorf("expected output weights to be restored")
 	}
 }
 
@@ -49,7 +68,7 @@ func TestSigmoid(t *testing.T) {
 }
 
 func TestRuneToArrayOfInts(t *testing.T) {
-	n := NewNetwork(25 * 25)
+	n := NewNetwork(25, 25)
 
 	expected := []int{0, 1, 0, 0, 0, 0, 0, 1}
 	actual := n.runeToArrayOfInts('A')
@@ -58,3 +77,55 @@ func TestRuneToArrayOfInts(t *testing.T) {
 		t.Fatalf("expected: %+v, got: %+v", expected, actual)
 	}
 }
+
+func TestCandidates(t *testing.T) {
+	n := NewNetwork(TileTargetWidth, TileTargetHeight)
+	img := image.NewGray(image.Rect(0, 0, TileTargetWidth, TileTargetHeight))
+
+	var all []rune
+	for r := rune(0); r < 1<<NumOutputs; r++ {
+		all = append(all, r)
+	}
+
+	// each output is the probability of its bit, so the confidences of every possible output sum to one
+	sum := 0.0
+	for _, c := range n.Candidates(img, string(all), 0) {
+		sum += c.Confidence
+	}
+
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("expected confidences to sum to 1, got: %f", sum)
+	}
+
+	candidates := n.Candidates(img, Alphabet, 5)
+	if len(candidates) != 5 {
+		t.Fatalf("expected 5 candidates, got: %d", len(candidates))
+	}
+
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Confidence > candidates[i-1].Confidence {
+			t.Errorf("expected candidates in order of confidence, got: %+v", candidates)
+		}
+	}
+}
+
+func TestBestCandidate(t *testing.T) {
+	n := NewNetwork(TileTargetWidth, TileTargetHeight)
+	img := image.NewGray(image.Rect(0, 0, TileTargetWidth, TileTargetHeight))
+
+	var all []rune
+	for r := rune(0); r < 1<<NumOutputs; r++ {
+		all = append(all, r)
+	}
+
+	best := n.BestCandidate(img)
+	top := n.Candidates(img, string(all), 1)[0]
+
+	if best.Char != n.Recognize(img) {
+		t.Errorf("expected %c, got: %c", n.Recognize(img), best.Char)
+	}
+
+	if best.Char != top.Char || math.Abs(best.Confidence-top.Confidence) > 1e-9 {
+		t.Errorf("expected %+v, got: %+v", top, best)
+	}
+}
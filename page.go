@@ -0,0 +1,142 @@
+package gocarina
+
+import (
+	"image"
+	"sort"
+)
+
+// MinLineHeightPercent is the fraction of the typical line height below which a run of non-blank rows is not taken
+// as a line of its own, but as part of the nearest line, e.g. the dots over a line of i's and j's.
+const MinLineHeightPercent = 0.4
+
+// PageLayout is the layout of the text on a page: its lines, top to bottom.
+type PageLayout struct {
+	Bounds image.Rectangle
+	Lines  []LineLayout
+}
+
+// LineLayout is the layout of a line of text: its words, left to right.
+type LineLayout struct {
+	Bounds image.Rectangle
+	Words  []WordLayout
+}
+
+// WordLayout is the layout of a word: the bounds of each of its glyphs, left to right.
+type WordLayout struct {
+	Bounds image.Rectangle
+	Glyphs []image.Rectangle
+}
+
+// SegmentPage splits a page of text into lines at the blank rows between them, then each line into glyphs at the
+// blank columns between them, grouping the glyphs into words as RecognizeLine does. All bounds are in the
+// coordinates of img, and are tight around the text they contain.
+func SegmentPage(img image.Image) PageLayout {
+	return segmentPage(NormalizePolarity(img))
+}
+
+// RecognizePage reads the text on a page, which may be dark on light or the reverse. Lines are separated by
+// newlines, and words by single spaces.
+func RecognizePage(n *Network, img image.Image) (string, error) {
+	result, err := recognizePageResult(n, img, false)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Text, nil
+}
+
+// segmentPage is SegmentPage for the black & white image bw.
+func segmentPage(bw image.Image) PageLayout {
+	var result PageLayout
+
+	for _, r := range pageLines(bw) {
+		line := segmentLine(bw.(interface {
+			SubImage(r image.Rectangle) image.Image
+		}).SubImage(r))
+
+		if len(line.Words) > 0 {
+			result.Lines = append(result.Lines, line)
+			result.Bounds = result.Bounds.Union(line.Bounds)
+		}
+	}
+
+	return result
+}
+
+// pageLines returns the bands of rows of bw that hold each line of text, top to bottom, each the full width of bw.
+func pageLines(bw image.Image) []image.Rectangle {
+	b := bw.Bounds()
+
+	var lines []image.Rectangle
+	for start, end := ImageSegmentH(bw, b.Min.Y); start < end; start, end = ImageSegmentH(bw, end) {
+		lines = append(lines, image.Rect(b.Min.X, start, b.Max.X, end))
+	}
+
+	if len(lines) < 2 {
+		return lines
+	}
+
+	var heights []int
+	for _, r := range lines {
+		heights = append(heights, r.Dy())
+	}
+	sort.Ints(heights)
+	minHeight := MinLineHeightPercent * float64(heights[len(heights)/2])
+
+	// merge each band that is too short to be a line into whichever neighbour is closer
+	for i := 0; i < len(lines); {
+		if len(lines) == 1 || float64(lines[i].Dy()) >= minHeight {
+			i++
+			continue
+		}
+
+		j := i - 1
+		if i == 0 || (i+1 < len(lines) && lines[i+1].Min.Y-lines[i].Max.Y < lines[i].Min.Y-lines[i-1].Max.Y) {
+			j = i + 1
+		}
+
+		lines[j] = lines[j].Union(lines[i])
+		lines = append(lines[:i], lines[i+1:]...)
+	}
+
+	return lines
+}
+
+// segmentLine splits the line of text in bw into words of glyphs.
+func segmentLine(bw image.Image) LineLayout {
+	var result LineLayout
+
+	glyphs := lineGlyphs(bw)
+	if len(glyphs) == 0 {
+		return result
+	}
+
+	height := 0
+	var gaps []int
+	for i, g := range glyphs {
+		if g.Dy() > height {
+			height = g.Dy()
+		}
+
+		if i > 0 {
+			gaps = append(gaps, g.Min.X-glyphs[i-1].Max.X)
+		}
+	}
+
+	threshold := spaceThreshold(gaps, height)
+
+	var word WordLayout
+	for i, g := range glyphs {
+		if i > 0 && float64(gaps[i-1]) >= threshold {
+			result.Words = append(result.Words, word)
+			word = WordLayout{}
+		}
+
+		word.Glyphs = append(word.Glyphs, g)
+		word.Bounds = word.Bounds.Union(g)
+		result.Bounds = result.Bounds.Union(g)
+	}
+	result.Words = append(result.Words, word)
+
+	return result
+}
@@ -0,0 +1,96 @@
+package gocarina
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestSegmentPage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 60))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.ZP, draw.Src)
+
+	glyphs := []image.Rectangle{
+		// first line: two words, the second glyph dotted like an i
+		image.Rect(5, 10, 10, 20), image.Rect(12, 12, 14, 20), image.Rect(30, 10, 35, 20),
+		// second line: one word
+		image.Rect(5, 35, 10, 45), image.Rect(12, 35, 17, 47),
+	}
+	for _, r := range glyphs {
+		draw.Draw(img, r, &image.Uniform{color.Black}, image.ZP, draw.Src)
+	}
+	dot := image.Rect(12, 8, 14, 10)
+	draw.Draw(img, dot, &image.Uniform{color.Black}, image.ZP, draw.Src)
+
+	page := SegmentPage(img)
+
+	if len(page.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got: %d", len(page.Lines))
+	}
+
+	examples := []struct {
+		line   LineLayout
+		bounds image.Rectangle
+		words  []int
+	}{
+		{page.Lines[0], image.Rect(5, 8, 35, 20), []int{2, 1}},
+		{page.Lines[1], image.Rect(5, 35, 17, 47), []int{2}},
+	}
+
+	for i, ex := range examples {
+		if ex.line.Bounds != ex.bounds {
+			t.Errorf("line %d: expected bounds %v, got: %v", i, ex.bounds, ex.line.Bounds)
+		}
+
+		var words []int
+		for _, word := range ex.line.Words {
+			words = append(words, len(word.Glyphs))
+		}
+
+		if len(words) != len(ex.words) {
+			t.Errorf("line %d: expected words of %v glyphs, got: %v", i, ex.words, words)
+			continue
+		}
+
+		for j := range words {
+			if words[j] != ex.words[j] {
+				t.Errorf("line %d: expected words of %v glyphs, got: %v", i, ex.words, words)
+			}
+		}
+	}
+
+	if g := page.Lines[0].Words[0].Glyphs[1]; g != image.Rect(12, 8, 14, 20) {
+		t.Errorf("expected the dot to be part of its glyph, got: %v", g)
+	}
+
+	if page.Bounds != image.Rect(5, 8, 35, 47) {
+		t.Errorf("expected page bounds %v, got: %v", image.Rect(5, 8, 35, 47), page.Bounds)
+	}
+}
+
+func TestRecognizePage(t *testing.T) {
+	tiles := ReadKnownBoards()
+
+	n := NewNetwork(TileTargetWidth, TileTargetHeight)
+	for _, tile := range tiles {
+		n.TrainTile(tile)
+	}
+
+	lines := []string{"CAT SAT", "ON THE", "MAT"}
+	img, glyphs := drawText(tiles, lines...)
+
+	got, err := RecognizePage(n, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := expectedText(n, lines, glyphs); got != expected {
+		t.Errorf("expected %q, got: %q", expected, got)
+	}
+
+	blank := image.NewRGBA(image.Rect(0, 0, 50, 50))
+	if _, err := RecognizePage(n, blank); err == nil {
+		t.Errorf("expected an error for a blank page")
+	}
+}
@@ -0,0 +1,233 @@
+package gocarina
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Query finds the words that can be formed from a pool of letters, as WordsFrom does, but only those that also meet
+// each of the constraints added to it. The constraint methods return the query, so they can be chained:
+//
+//	words, err := NewQuery("lettersonboard").Length(5, 0).Matching("?r??e").Words()
+type Query struct {
+	chars      string
+	board      *Board
+	dictionary *Dictionary
+	including  string // letters every word must contain
+	tiles      []int  // tiles of board every play must use
+	minLength  int
+	maxLength  int
+	prefix     string
+	suffix     string
+	substring  string
+	pattern    string
+	ranker     Ranker
+	limit      int
+}
+
+// NewQuery returns a query for the words that can be formed from the given chars.
+func NewQuery(chars string) *Query {
+	return &Query{chars: strings.ToLower(chars)}
+}
+
+// Query returns a query for the words that can be formed from the letters on the board.
+func (b *Board) Query() *Query {
+	q := NewQuery(b.Letters())
+	q.board = b
+
+	return q
+}
+
+// WithDictionary sets the dictionary to search, in place of the dictionary for DefaultLanguage.
+func (q *Query) WithDictionary(d *Dictionary) *Query {
+	q.dictionary = d
+	return q
+}
+
+// Including requires words to contain all of the given letters. A letter given twice must appear twice.
+func (q *Query) Including(letters string) *Query {
+	q.including += strings.ToLower(letters)
+	return q
+}
+
+// UsingTiles requires plays to use the board tiles at the given indices, e.g. to capture them. Only queries from
+// Board.Query can use tiles. Words must contain the tiles' letters, and Plays puts the tiles themselves to use.
+func (q *Query) UsingTiles(tiles ...int) *Query {
+	q.tiles = append(q.tiles, tiles...)
+	return q
+}
+
+// Length requires words to have between min and max letters, inclusive. Zero means no limit.
+func (q *Query) Length(min, max int) *Query {
+	q.minLength, q.maxLength = min, max
+	return q
+}
+
+// StartingWith requires words to start with prefix.
+func (q *Query) StartingWith(prefix string) *Query {
+	q.prefix = strings.ToLower(prefix)
+	return q
+}
+
+// EndingWith requires words to end with suffix.
+func (q *Query) EndingWith(suffix string) *Query {
+	q.suffix = strings.ToLower(suffix)
+	return q
+}
+
+// WithSubstring requires words to contain s, with its letters together and in order.
+func (q *Query) WithSubstring(s string) *Query {
+	q.substring = strings.ToLower(s)
+	return q
+}
+
+// Matching requires words to match the glob pattern, in which '?' stands for any one letter, and '*' for any number
+// of them. E.g. "?r??e" matches "arose" and "bribe". The pattern syntax is that of path.Match.
+func (q *Query) Matching(pattern string) *Query {
+	q.pattern = strings.ToLower(pattern)
+	return q
+}
+
+// RankedBy orders the words from Words by r, in place of ByWordLength.
+func (q *Query) RankedBy(r Ranker) *Query {
+	q.ranker = r
+	return q
+}
+
+// Limit keeps only the first n results of Words or Plays, once ordered. Zero means no limit.
+func (q *Query) Limit(n int) *Query {
+	q.limit = n
+	return q
+}
+
+// Words returns the words that meet every constraint of the query, ordered ByWordLength, or by the query's Ranker.
+func (q *Query) Words() ([]string, error) {
+	words, err := q.words()
+	if err != nil {
+		return nil, err
+	}
+
+	if q.ranker != nil {
+		return Rank(words, q.ranker, q.limit), nil
+	}
+
+	if q.limit > 0 && len(words) > q.limit {
+		words = words[:q.limit]
+	}
+
+	return words, nil
+}
+
+// words returns the words that meet every constraint of the query, ordered ByWordLength, and without any limit.
+func (q *Query) words() ([]string, error) {
+	if err := q.validate(); err != nil {
+		return nil, err
+	}
+
+	d := q.dictionary
+	if d == nil {
+		d = defaultLanguageDictionary()
+	}
+
+	// the board's Wildcard tiles may be limited to their Candidates
+	pool := newLetterPool(q.chars)
+	if q.board != nil {
+		pool = q.board.letterPool()
+	}
+
+	// wordsFrom returns its words ByWordLength already, and filtering them doesn't change the order
+	var result []string
+	for _, word := range d.wordsFrom(pool) {
+		if q.matches(word) {
+			result = append(result, word)
+		}
+	}
+
+	return result, nil
+}
+
+// Plays returns the best play for player of each word that meets every constraint of the query, using all of the
+// tiles required by UsingTiles. Plays are always ordered ByPlayScore, whatever the query's Ranker. Only queries from
+// Board.Query can be played.
+func (q *Query) Plays(player Owner) ([]Play, error) {
+	if q.board == nil {
+		return nil, errors.New("query has no board to play on")
+	}
+
+	words, err := q.words()
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewSolver(q.board, player)
+
+	var result []Play
+	for _, word := range words {
+		if p, ok := s.PlayUsing(word, q.tiles); ok {
+			result = append(result, p)
+		}
+	}
+
+	sort.Sort(ByPlayScore(result))
+
+	if q.limit > 0 && len(result) > q.limit {
+		result = result[:q.limit]
+	}
+
+	return result, nil
+}
+
+// validate checks the query's pattern and tiles, before any words are searched.
+func (q *Query) validate() error {
+	if q.pattern != "" {
+		if _, err := path.Match(q.pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %s", q.pattern, err)
+		}
+	}
+
+	if len(q.tiles) > 0 && q.board == nil {
+		return fmt.Errorf("query has no board for tiles %v", q.tiles)
+	}
+
+	for _, t := range q.tiles {
+		if t < 0 || (q.board != nil && t >= len(q.board.Tiles)) {
+			return fmt.Errorf("no such tile: %d", t)
+		}
+	}
+
+	return nil
+}
+
+// matches returns true if word meets every constraint of the query. The word is assumed to be formed from the
+// query's chars already.
+func (q *Query) matches(word string) bool {
+	n := utf8.RuneCountInString(word)
+	if n < q.minLength || (q.maxLength > 0 && n > q.maxLength) {
+		return false
+	}
+
+	if !strings.HasPrefix(word, q.prefix) || !strings.HasSuffix(word, q.suffix) || !strings.Contains(word, q.substring) {
+		return false
+	}
+
+	if q.pattern != "" {
+		if ok, _ := path.Match(q.pattern, word); !ok {
+			return false
+		}
+	}
+
+	// the required letters, and those of the required tiles, must all be found in the word. Wildcard tiles could be
+	// any letter, so are left for Plays to check.
+	including := q.including
+	for _, t := range q.tiles {
+		if letter := q.board.Tiles[t].Letter; letter != Wildcard {
+			including += strings.ToLower(string(letter))
+		}
+	}
+
+	return CanMakeWordFrom(including, word)
+}
@@ -0,0 +1,144 @@
+package gocarina
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueryWords(t *testing.T) {
+	d := NewDictionary([]string{"arose", "bribe", "arise", "rose", "sore", "ore", "roe", "soar", "oars"})
+
+	var examples = []struct {
+		name     string
+		q        *Query
+		expected []string
+	}{
+		{"all", NewQuery("AROSE"), []string{"arose", "oars", "rose", "soar", "sore", "ore", "roe"}},
+		{"including", NewQuery("arose").Including("sa"), []string{"arose", "oars", "soar"}},
+		{"including twice", NewQuery("arosee").Including("ee"), nil},
+		{"length", NewQuery("arose").Length(4, 4), []string{"oars", "rose", "soar", "sore"}},
+		{"min length", NewQuery("arose").Length(5, 0), []string{"arose"}},
+		{"starting", NewQuery("arose").StartingWith("So"), []string{"soar", "sore"}},
+		{"ending", NewQuery("arose").EndingWith("e"), []string{"arose", "rose", "sore", "ore", "roe"}},
+		{"substring", NewQuery("arose").WithSubstring("or"), []string{"sore", "ore"}},
+		{"pattern", NewQuery("arosebrib").Matching("?R??E"), []string{"arise", "arose", "bribe"}},
+		{"star", NewQuery("arose").Matching("*s"), []string{"oars"}},
+		{"combined", NewQuery("arose").Length(4, 0).EndingWith("e").Including("a"), []string{"arose"}},
+	}
+
+	for _, ex := range examples {
+		actual, err := ex.q.WithDictionary(d).Words()
+		if err != nil {
+			t.Errorf("%s: %s", ex.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(ex.expected, actual) {
+			t.Errorf("%s: expected: %v, got: %v", ex.name, ex.expected, actual)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	var examples = []struct {
+		name string
+		q    *Query
+	}{
+		{"bad pattern", NewQuery("arose").Matching("[a")},
+		{"tiles without a board", NewQuery("arose").UsingTiles(0)},
+		{"no such tile", NewBoard(1, 3, "CAT", "...").Query().UsingTiles(3)},
+	}
+
+	for _, ex := range examples {
+		if _, err := ex.q.Words(); err == nil {
+			t.Errorf("%s: expected an error", ex.name)
+		}
+	}
+
+	if _, err := NewQuery("arose").Plays(Blue); err == nil {
+		t.Errorf("expected an error playing a query without a board")
+	}
+}
+
+func TestQueryPlays(t *testing.T) {
+	b := NewBoard(2, 3,
+		"CAT"+
+			"SAT",
+		"..."+
+			"r..")
+
+	d := NewDictionary([]string{"cat", "sat", "at", "cats"})
+
+	// the A at index 1 would be the solver's own choice; require the one at index 4 instead
+	plays, err := b.Query().WithDictionary(d).UsingTiles(4, 3).Plays(Blue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var words []string
+	for _, p := range plays {
+		words = append(words, p.Word)
+
+		used := map[int]bool{}
+		for _, i := range p.Tiles {
+			used[i] = true
+		}
+
+		if !used[3] || !used[4] {
+			t.Errorf("%q: expected tiles 3 and 4 to be used, got: %v", p.Word, p.Tiles)
+		}
+	}
+
+	expected := []string{"cats", "sat"}
+	if !reflect.DeepEqual(expected, words) {
+		t.Errorf("expected: %v, got: %v", expected, words)
+	}
+}
+
+func TestSolverPlayUsing(t *testing.T) {
+	b := NewBoard(1, 4, "ABBA", "....")
+	s := NewSolver(b, Blue)
+
+	p, ok := s.PlayUsing("ab", []int{3})
+	if !ok {
+		t.Fatalf("expected to be able to play %q", "ab")
+	}
+
+	if !reflect.DeepEqual([]int{3, 2}, p.Tiles) && !reflect.DeepEqual([]int{3, 1}, p.Tiles) {
+		t.Errorf("expected the A at tile 3 to be used, got: %v", p.Tiles)
+	}
+
+	if _, ok := s.PlayUsing("ab", []int{0, 3}); ok {
+		t.Errorf("expected not to be able to use both As in %q", "ab")
+	}
+}
+
+func TestSolverPlayUsingWildcard(t *testing.T) {
+	b := NewBoard(1, 2, "A?", "..")
+	b.Tiles[1].Candidates = []rune{'A', 'B'}
+	s := NewSolver(b, Blue)
+
+	// the wildcard could stand in for the A, but then nothing would be left for the B
+	p, ok := s.PlayUsing("ab", []int{1})
+	if !ok {
+		t.Fatalf("expected to be able to play %q", "ab")
+	}
+
+	if !reflect.DeepEqual([]int{0, 1}, p.Tiles) {
+		t.Errorf("expected tiles: %v, got: %v", []int{0, 1}, p.Tiles)
+	}
+
+	expected := []Substitution{{Index: 1, Letter: 'b'}}
+	if !reflect.DeepEqual(expected, p.Substitutions) {
+		t.Errorf("expected substitutions: %v, got: %v", expected, p.Substitutions)
+	}
+
+	plays, err := b.Query().WithDictionary(NewDictionary([]string{"ab"})).UsingTiles(1).Plays(Blue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plays) != 1 || plays[0].Word != "ab" {
+		t.Errorf("expected to find %q using the wildcard, got: %v", "ab", plays)
+	}
+}
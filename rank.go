@@ -0,0 +1,148 @@
+package gocarina
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ranker gives each word a rank; words with a higher rank are better.
+type Ranker interface {
+	Rank(word string) float64
+}
+
+// RankerFunc adapts an ordinary function to the Ranker interface.
+type RankerFunc func(word string) float64
+
+func (f RankerFunc) Rank(word string) float64 {
+	return f(word)
+}
+
+// ScrabbleValues are the points for each letter in English-language Scrabble.
+var ScrabbleValues = map[rune]int{
+	'a': 1, 'b': 3, 'c': 3, 'd': 2, 'e': 1, 'f': 4, 'g': 2, 'h': 4, 'i': 1, 'j': 8, 'k': 5, 'l': 1, 'm': 3,
+	'n': 1, 'o': 1, 'p': 3, 'q': 10, 'r': 1, 's': 1, 't': 1, 'u': 1, 'v': 4, 'w': 4, 'x': 8, 'y': 4, 'z': 10,
+}
+
+// LetterFrequencies are the relative frequencies of letters in English text, as percentages.
+var LetterFrequencies = map[rune]float64{
+	'a': 8.17, 'b': 1.49, 'c': 2.78, 'd': 4.25, 'e': 12.70, 'f': 2.23, 'g': 2.02, 'h': 6.09, 'i': 6.97,
+	'j': 0.15, 'k': 0.77, 'l': 4.03, 'm': 2.41, 'n': 6.75, 'o': 7.51, 'p': 1.93, 'q': 0.10, 'r': 5.99,
+	's': 6.33, 't': 9.06, 'u': 2.76, 'v': 0.98, 'w': 2.36, 'x': 0.15, 'y': 1.97, 'z': 0.07,
+}
+
+var (
+	// LengthRanker ranks words by their number of letters.
+	LengthRanker Ranker = RankerFunc(func(word string) float64 {
+		return float64(utf8.RuneCountInString(word))
+	})
+
+	// ScrabbleRanker ranks words by the sum of their ScrabbleValues.
+	ScrabbleRanker Ranker = RankerFunc(func(word string) float64 {
+		score := 0
+		for _, c := range word {
+			score += ScrabbleValues[unicode.ToLower(c)]
+		}
+
+		return float64(score)
+	})
+
+	// RarityRanker ranks words by how common their letters are, by LetterFrequencies: each letter counts for its
+	// frequency relative to that of 'e'. So long words of common letters come first, and words that would use up
+	// rare letters such as Q, Z and X (which are hard to play, and best kept for later) come last.
+	RarityRanker Ranker = RankerFunc(func(word string) float64 {
+		rank := 0.0
+		for _, c := range word {
+			rank += LetterFrequencies[unicode.ToLower(c)] / LetterFrequencies['e']
+		}
+
+		return rank
+	})
+)
+
+// FrequencyRanker ranks words by how often they are used, so that familiar words come before obscure ones. Words
+// it has no count for rank last.
+type FrequencyRanker map[string]float64
+
+func (f FrequencyRanker) Rank(word string) float64 {
+	return f[strings.ToLower(word)]
+}
+
+// ReadWordFrequencies reads a FrequencyRanker from lines of a word and its count, separated by whitespace.
+func ReadWordFrequencies(r io.Reader) (FrequencyRanker, error) {
+	result := make(FrequencyRanker)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected a word and a count, got: %q", line, scanner.Text())
+		}
+
+		count, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid count: %s", line, err)
+		}
+
+		result[strings.ToLower(fields[0])] = count
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading word frequencies: %s", err)
+	}
+
+	return result, nil
+}
+
+// BoardGainRanker ranks words by what the solver's player would gain by playing them on its board: by Play.Score,
+// then by the number of tiles defended, as for ByPlayScore. Words that can't be played rank last.
+func BoardGainRanker(s *Solver) Ranker {
+	return RankerFunc(func(word string) float64 {
+		p, ok := s.Play(word)
+		if !ok {
+			return math.Inf(-1)
+		}
+
+		// there are never as many as 1000 tiles defended
+		return float64(p.Score())*1000 + float64(p.Defended)
+	})
+}
+
+// Rank orders words by r, best first, keeping at most limit of them; a limit of 0 keeps them all. Words of equal
+// rank are ordered ByWordLength. The words slice is left unchanged.
+func Rank(words []string, r Ranker, limit int) []string {
+	ranks := make(map[string]float64, len(words))
+	for _, word := range words {
+		ranks[word] = r.Rank(word)
+	}
+
+	result := append([]string(nil), words...)
+	sort.Slice(result, func(i, j int) bool {
+		if ri, rj := ranks[result[i]], ranks[result[j]]; ri != rj {
+			return ri > rj
+		}
+
+		return ByWordLength(result).Less(i, j)
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
+
+// WordsFromRanked is like WordsFrom, but orders the words by r, and returns at most limit of them.
+func WordsFromRanked(chars string, r Ranker, limit int) []string {
+	return Rank(WordsFrom(chars), r, limit)
+}
@@ -0,0 +1,82 @@
+package gocarina
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRankers(t *testing.T) {
+	words := []string{"quiz", "tea", "eat", "seat", "ox"}
+
+	var examples = []struct {
+		name     string
+		r        Ranker
+		limit    int
+		expected []string
+	}{
+		{"length", LengthRanker, 0, []string{"quiz", "seat", "eat", "tea", "ox"}},
+		{"scrabble", ScrabbleRanker, 0, []string{"quiz", "ox", "seat", "eat", "tea"}},
+		{"rarity", RarityRanker, 0, []string{"seat", "eat", "tea", "quiz", "ox"}},
+		{"limit", LengthRanker, 2, []string{"quiz", "seat"}},
+		{"limit beyond length", LengthRanker, 10, []string{"quiz", "seat", "eat", "tea", "ox"}},
+	}
+
+	for _, ex := range examples {
+		actual := Rank(words, ex.r, ex.limit)
+		if !reflect.DeepEqual(ex.expected, actual) {
+			t.Errorf("%s: expected: %v, got: %v", ex.name, ex.expected, actual)
+		}
+	}
+
+	if words[0] != "quiz" || words[4] != "ox" {
+		t.Errorf("expected Rank to leave its input alone, got: %v", words)
+	}
+}
+
+func TestFrequencyRanker(t *testing.T) {
+	f, err := ReadWordFrequencies(strings.NewReader("the 1000\nTea 50\n\neat 200\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"eat", "tea", "ate"}
+	if actual := Rank([]string{"ate", "tea", "eat"}, f, 0); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	for _, bad := range []string{"the", "the lots"} {
+		if _, err := ReadWordFrequencies(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestBoardGainRanker(t *testing.T) {
+	b := NewBoard(1, 5, "CATSX", "rrb..")
+
+	// "cat" captures two tiles from red, "sat" only one, and "tax" can't be played
+	expected := []string{"cat", "sat", "tax"}
+	if actual := Rank([]string{"tax", "sat", "cat"}, BoardGainRanker(NewSolver(b, Blue)), 0); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestQueryRankedBy(t *testing.T) {
+	d := NewDictionary([]string{"quiz", "tea", "eat", "seat", "ox"})
+
+	actual, err := NewQuery("quizteasox").WithDictionary(d).RankedBy(ScrabbleRanker).Limit(2).Words()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"quiz", "ox"}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	actual, _ = NewQuery("quizteasox").WithDictionary(d).Limit(1).Words()
+	if !reflect.DeepEqual([]string{"quiz"}, actual) {
+		t.Errorf("expected: %v, got: %v", []string{"quiz"}, actual)
+	}
+}
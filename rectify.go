@@ -0,0 +1,262 @@
+package gocarina
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+const (
+	BackgroundTolerance = 12   // max summed RGB difference (0..255 per channel) for a pixel to count as background
+	MinGridAreaPercent  = 0.10 // the grid must cover at least this fraction of the image to be detected
+)
+
+// Rectify finds the quadrilateral of the game grid in a (possibly keystoned) camera image of a board, and applies a
+// projective warp to map it onto the grid area of a canonical screenshot, as described by DefaultLayout. The rest
+// of the canvas is filled with the background color, so the result can be tiled like any other screenshot.
+func Rectify(img image.Image) (image.Image, error) {
+	bg := BackgroundColor(img)
+
+	corners, err := findGridCorners(img)
+	if err != nil {
+		return nil, err
+	}
+
+	grid := DefaultLayout.Bounds()
+	canonical := [4]point{
+		{float64(grid.Min.X), float64(grid.Min.Y)},
+		{float64(grid.Max.X), float64(grid.Min.Y)},
+		{float64(grid.Max.X), float64(grid.Max.Y)},
+		{float64(grid.Min.X), float64(grid.Max.Y)},
+	}
+
+	// we map each destination pixel back to the source image, so solve for canonical -> source
+	h, err := solveHomography(canonical, corners)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := image.NewRGBA(image.Rectangle{Max: DefaultLayout.Canvas})
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{bg}, image.ZP, draw.Src)
+
+	b := img.Bounds()
+	for y := grid.Min.Y; y < grid.Max.Y; y++ {
+		for x := grid.Min.X; x < grid.Max.X; x++ {
+			sx, sy := h.apply(float64(x)+0.5, float64(y)+0.5)
+			p := image.Pt(int(math.Floor(sx)), int(math.Floor(sy)))
+
+			if p.In(b) {
+				dst.Set(x, y, img.At(p.X, p.Y))
+			}
+		}
+	}
+
+	return dst, nil
+}
+
+// BackgroundColor returns the most common color among the pixels along the edges of img.
+func BackgroundColor(img image.Image) color.Color {
+	b := img.Bounds()
+	counts := make(map[color.RGBA]int)
+
+	sample := func(x, y int) {
+		r, g, bl, _ := img.At(x, y).RGBA()
+		counts[color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(bl >> 8), 0xff}]++
+	}
+
+	for x := b.Min.X; x < b.Max.X; x++ {
+		sample(x, b.Min.Y)
+		sample(x, b.Max.Y-1)
+	}
+
+	for y := b.Min.Y + 1; y < b.Max.Y-1; y++ {
+		sample(b.Min.X, y)
+		sample(b.Max.X-1, y)
+	}
+
+	var result color.RGBA
+	best := -1
+	for c, n := range counts {
+		if n > best || (n == best && colorLess(c, result)) {
+			result, best = c, n
+		}
+	}
+
+	return result
+}
+
+// colorLess gives ties in BackgroundColor a stable winner, as map iteration order is random.
+func colorLess(a, b color.RGBA) bool {
+	if a.R != b.R {
+		return a.R < b.R
+	}
+	if a.G != b.G {
+		return a.G < b.G
+	}
+	return a.B < b.B
+}
+
+// colorDistance returns the summed difference of the RGB channels of a and b, on a 0..255 scale.
+func colorDistance(a, b color.Color) int {
+	ar, ag, ab, _ := a.RGBA()
+	br, bg, bb, _ := b.RGBA()
+
+	diff := func(x, y uint32) int {
+		d := int(x>>8) - int(y>>8)
+		if d < 0 {
+			return -d
+		}
+		return d
+	}
+
+	return diff(ar, br) + diff(ag, bg) + diff(ab, bb)
+}
+
+// ForegroundMask returns a black & white image in which every pixel that differs noticeably from bg is black.
+func ForegroundMask(img image.Image, bg color.Color) *image.Gray {
+	b := img.Bounds()
+	mask := image.NewGray(b)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if colorDistance(img.At(x, y), bg) > BackgroundTolerance {
+				mask.SetGray(x, y, color.Gray{0})
+			} else {
+				mask.SetGray(x, y, color.Gray{0xff})
+			}
+		}
+	}
+
+	return mask
+}
+
+// largestForeground labels the pixels of img that differ from its background color, and returns the largest
+// connected region of them. The region is nil if it covers less than MinGridAreaPercent of the image.
+func largestForeground(img image.Image) (*Components, *Component) {
+	comps := LabelComponents(ForegroundMask(img, BackgroundColor(img)), FourConnected)
+
+	var largest *Component
+	for i, comp := range comps.List {
+		if largest == nil || comp.Area > largest.Area {
+			largest = &comps.List[i]
+		}
+	}
+
+	b := img.Bounds()
+	if largest == nil || float64(largest.Area) < MinGridAreaPercent*float64(b.Dx()*b.Dy()) {
+		return comps, nil
+	}
+
+	return comps, largest
+}
+
+// findGridCorners returns the top-left, top-right, bottom-right and bottom-left corners of the grid, which is taken
+// to be the largest connected region of non-background pixels.
+func findGridCorners(img image.Image) (corners [4]point, err error) {
+	comps, grid := largestForeground(img)
+	if grid == nil {
+		return corners, errors.New("no board grid found")
+	}
+
+	// the corners are the pixels that are extreme along the diagonals
+	var tl, tr, br, bl image.Point
+	first := true
+
+	for y := grid.Bounds.Min.Y; y < grid.Bounds.Max.Y; y++ {
+		for x := grid.Bounds.Min.X; x < grid.Bounds.Max.X; x++ {
+			if comps.LabelAt(x, y) != grid.Label {
+				continue
+			}
+
+			if first {
+				tl, tr, br, bl = image.Pt(x, y), image.Pt(x, y), image.Pt(x, y), image.Pt(x, y)
+				first = false
+			}
+
+			if x+y < tl.X+tl.Y {
+				tl = image.Pt(x, y)
+			}
+			if x+y > br.X+br.Y {
+				br = image.Pt(x, y)
+			}
+			if x-y > tr.X-tr.Y {
+				tr = image.Pt(x, y)
+			}
+			if x-y < bl.X-bl.Y {
+				bl = image.Pt(x, y)
+			}
+		}
+	}
+
+	// use the outer edges of the corner pixels
+	corners = [4]point{
+		{float64(tl.X), float64(tl.Y)},
+		{float64(tr.X + 1), float64(tr.Y)},
+		{float64(br.X + 1), float64(br.Y + 1)},
+		{float64(bl.X), float64(bl.Y + 1)},
+	}
+
+	return corners, nil
+}
+
+type point struct {
+	X, Y float64
+}
+
+// homography is a 3x3 projective transform in row-major order, normalized so that the last element is 1.
+type homography [9]float64
+
+func (h homography) apply(x, y float64) (float64, float64) {
+	w := h[6]*x + h[7]*y + h[8]
+	return (h[0]*x + h[1]*y + h[2]) / w, (h[3]*x + h[4]*y + h[5]) / w
+}
+
+// solveHomography finds the projective transform that maps each point in from onto the corresponding point in to.
+func solveHomography(from, to [4]point) (homography, error) {
+	// two equations per correspondence, for the eight unknowns h[0..7]; the last column is the right-hand side
+	var m [8][9]float64
+	for i := 0; i < 4; i++ {
+		x, y := from[i].X, from[i].Y
+		u, v := to[i].X, to[i].Y
+
+		m[2*i] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		m[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+
+	// Gaussian elimination with partial pivoting
+	for col := 0; col < 8; col++ {
+		pivot := col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			return homography{}, errors.New("degenerate grid corners")
+		}
+
+		m[col], m[pivot] = m[pivot], m[col]
+
+		for row := 0; row < 8; row++ {
+			if row == col {
+				continue
+			}
+
+			f := m[row][col] / m[col][col]
+			for k := col; k < 9; k++ {
+				m[row][k] -= f * m[col][k]
+			}
+		}
+	}
+
+	var h homography
+	for i := 0; i < 8; i++ {
+		h[i] = m[i][8] / m[i][i]
+	}
+	h[8] = 1
+
+	return h, nil
+}
@@ -0,0 +1,214 @@
+package gocarina
+
+import (
+	"errors"
+	"image"
+	"strings"
+)
+
+// MaxAlternatives is the most alternatives given for each character of a Result.
+const MaxAlternatives = 3
+
+// printable are the characters that alternatives are chosen from when reading text.
+var printable = func() string {
+	var result []rune
+	for c := '!'; c <= '~'; c++ {
+		result = append(result, c)
+	}
+
+	return string(result)
+}()
+
+// Level is how much of an image a Result covers.
+type Level int
+
+const (
+	PageLevel Level = iota
+	LineLevel
+	WordLevel
+	CharLevel
+)
+
+func (l Level) String() string {
+	switch l {
+	case PageLevel:
+		return "page"
+	case LineLevel:
+		return "line"
+	case WordLevel:
+		return "word"
+	case CharLevel:
+		return "char"
+	}
+
+	return "unknown"
+}
+
+// Result is what was recognized in some part of an image: a page, or a line, word or character of it. Together
+// they form a tree, so that each character read can be traced back to where it was found in the image.
+type Result struct {
+	Level        Level
+	Text         string          // lines are separated by newlines, and words by spaces
+	Bounds       image.Rectangle // in the coordinates of the source image; for a page, the whole of it
+	Confidence   float64         // from 0 to 1; for anything bigger than a char, that of its least confident char
+	Alternatives []Candidate     // for a char, the next most likely characters, best first
+	Row, Col     int             // for the tiles of a board, and the lines and words they make up; otherwise -1
+	Children     []*Result       // the lines of a page, the words of a line, or the chars of a word, in order
+}
+
+// newResult returns a Result for children, which are joined by sep to make its text.
+func newResult(level Level, children []*Result, sep string) *Result {
+	result := &Result{Level: level, Confidence: 1, Row: -1, Col: -1, Children: children}
+
+	var text []string
+	for _, child := range children {
+		text = append(text, child.Text)
+		result.Bounds = result.Bounds.Union(child.Bounds)
+		if child.Confidence < result.Confidence {
+			result.Confidence = child.Confidence
+		}
+	}
+	result.Text = strings.Join(text, sep)
+
+	return result
+}
+
+// charResult returns a Result for a char read with the given candidates, best first.
+func charResult(r image.Rectangle, candidates []Candidate) *Result {
+	result := &Result{
+		Level:      CharLevel,
+		Text:       string(candidates[0].Char),
+		Bounds:     r,
+		Confidence: candidates[0].Confidence,
+		Row:        -1,
+		Col:        -1,
+	}
+
+	for _, c := range candidates[1:] {
+		if len(result.Alternatives) < MaxAlternatives {
+			result.Alternatives = append(result.Alternatives, c)
+		}
+	}
+
+	return result
+}
+
+// Chars returns the chars of the result, in order.
+func (r *Result) Chars() []*Result {
+	if r.Level == CharLevel {
+		return []*Result{r}
+	}
+
+	var result []*Result
+	for _, child := range r.Children {
+		result = append(result, child.Chars()...)
+	}
+
+	return result
+}
+
+// RecognizeLineResult is like RecognizeLine, but returns a Result for the line.
+func RecognizeLineResult(n *Network, img image.Image) (*Result, error) {
+	return recognizeLineResult(n, img, true)
+}
+
+// RecognizePageResult is like RecognizePage, but returns a Result for the page.
+func RecognizePageResult(n *Network, img image.Image) (*Result, error) {
+	return recognizePageResult(n, img, true)
+}
+
+// recognizeLineResult is RecognizeLineResult, giving the alternatives for each char only if alternatives is true, as
+// they take another pass of the network over each glyph.
+func recognizeLineResult(n *Network, img image.Image, alternatives bool) (*Result, error) {
+	bw := NormalizePolarity(img)
+
+	line := segmentLine(bw)
+	if len(line.Words) == 0 {
+		return nil, errors.New("no text found in line")
+	}
+
+	return lineResult(n, bw, line, alternatives), nil
+}
+
+// recognizePageResult is RecognizePageResult, giving the alternatives for each char only if alternatives is true.
+func recognizePageResult(n *Network, img image.Image, alternatives bool) (*Result, error) {
+	bw := NormalizePolarity(img)
+
+	page := segmentPage(bw)
+	if len(page.Lines) == 0 {
+		return nil, errors.New("no text found on page")
+	}
+
+	var lines []*Result
+	for _, line := range page.Lines {
+		lines = append(lines, lineResult(n, bw, line, alternatives))
+	}
+
+	result := newResult(PageLevel, lines, "\n")
+	result.Bounds = img.Bounds()
+
+	return result, nil
+}
+
+// lineResult reads the words of line from the black & white image bw, with the alternatives for each char if
+// alternatives is true.
+func lineResult(n *Network, bw image.Image, line LineLayout, alternatives bool) *Result {
+	var words []*Result
+	for _, word := range line.Words {
+		glyphs, best := segmentWord(n, bw, word, line.Bounds.Dy())
+
+		var chars []*Result
+		for i, g := range glyphs {
+			candidates := []Candidate{best[i]}
+			if alternatives {
+				candidates = glyphCandidates(n, bw, g, best[i])
+			}
+
+			chars = append(chars, charResult(g, candidates))
+		}
+
+		words = append(words, newResult(WordLevel, chars, ""))
+	}
+
+	return newResult(LineLevel, words, " ")
+}
+
+// glyphCandidates returns what the network reads the glyph within r of bw as, best first: the best candidate, then
+// the next most likely printable characters.
+func glyphCandidates(n *Network, bw image.Image, r image.Rectangle, best Candidate) []Candidate {
+	result := []Candidate{best}
+
+	for _, c := range n.Candidates(reduceGlyph(bw, r, n.Normalization), printable, MaxAlternatives+1) {
+		if c.Char != best.Char && len(result) <= MaxAlternatives {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// Result returns what the network reads on each tile of the board, as a page with a line for each row of tiles,
+// each a single word. The bounds are those in the board image, after any deskewing or rectifying.
+func (b *Board) Result(n *Network) *Result {
+	var lines []*Result
+	for row := 0; row < b.Rows(); row++ {
+		var chars []*Result
+		for col := 0; col < b.Cols(); col++ {
+			tile := b.Tiles[b.Index(row, col)]
+
+			char := charResult(b.Grid.TileRect(row, col), n.TileCandidates(tile, Alphabet, MaxAlternatives+1))
+			char.Row, char.Col = row, col
+			chars = append(chars, char)
+		}
+
+		word := newResult(WordLevel, chars, "")
+		line := newResult(LineLevel, []*Result{word}, " ")
+		word.Row, line.Row = row, row
+		lines = append(lines, line)
+	}
+
+	result := newResult(PageLevel, lines, "\n")
+	result.Bounds = b.img.Bounds()
+
+	return result
+}
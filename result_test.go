@@ -0,0 +1,111 @@
+package gocarina
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecognizePageResult(t *testing.T) {
+	tiles := ReadKnownBoards()
+
+	n := NewNetwork(TileTargetWidth, TileTargetHeight)
+	for _, tile := range tiles {
+		n.TrainTile(tile)
+	}
+
+	lines := []string{"CAT SAT", "ON MAT"}
+	img, _ := drawText(tiles, lines...)
+
+	page, err := RecognizePageResult(n, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := RecognizePage(n, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.Text != text {
+		t.Errorf("expected %q, got: %q", text, page.Text)
+	}
+
+	if page.Bounds != img.Bounds() {
+		t.Errorf("expected the page to cover the image %v, got: %v", img.Bounds(), page.Bounds)
+	}
+
+	if page.Level != PageLevel || len(page.Children) != len(lines) {
+		t.Fatalf("expected a page of %d lines, got: %s of %d", len(lines), page.Level, len(page.Children))
+	}
+
+	for i, line := range page.Children {
+		if words := len(strings.Fields(lines[i])); line.Level != LineLevel || len(line.Children) != words {
+			t.Errorf("line %d: expected %d words, got: %s of %d", i, words, line.Level, len(line.Children))
+		}
+
+		for _, word := range line.Children {
+			for _, char := range word.Children {
+				if char.Level != CharLevel || !char.Bounds.In(word.Bounds) || !word.Bounds.In(line.Bounds) {
+					t.Errorf("expected %s %v in word %v in line %v", char.Level, char.Bounds, word.Bounds, line.Bounds)
+				}
+
+				if char.Confidence < word.Confidence || word.Confidence < line.Confidence {
+					t.Errorf("expected confidences to be those of the least confident chars")
+				}
+
+				if char.Row != -1 || char.Col != -1 {
+					t.Errorf("expected no row or column, got: %d, %d", char.Row, char.Col)
+				}
+
+				if len(char.Alternatives) > MaxAlternatives {
+					t.Errorf("expected at most %d alternatives, got: %d", MaxAlternatives, len(char.Alternatives))
+				}
+
+				for _, alt := range char.Alternatives {
+					if string(alt.Char) == char.Text || alt.Confidence > char.Confidence {
+						t.Errorf("expected alternatives to %q to be other, less likely chars, got: %+v", char.Text, alt)
+					}
+				}
+			}
+		}
+	}
+
+	if chars := len(page.Chars()); chars != len(strings.Join(strings.Fields(strings.Join(lines, " ")), "")) {
+		t.Errorf("expected a char for each letter, got: %d", chars)
+	}
+}
+
+func TestBoardResult(t *testing.T) {
+	n := NewNetwork(TileTargetWidth, TileTargetHeight)
+	for _, tile := range ReadKnownBoards() {
+		n.TrainTile(tile)
+	}
+
+	b := ReadUnknownBoard("board-images/board3.png")
+	page := b.Result(n)
+
+	if len(page.Children) != b.Rows() {
+		t.Fatalf("expected %d lines, got: %d", b.Rows(), len(page.Children))
+	}
+
+	chars := page.Chars()
+	if len(chars) != len(b.Tiles) {
+		t.Fatalf("expected %d chars, got: %d", len(b.Tiles), len(chars))
+	}
+
+	for i, char := range chars {
+		row, col := b.Position(i)
+		if char.Row != row || char.Col != col || char.Bounds != b.Grid.TileRect(row, col) {
+			t.Errorf("tile %d: expected row %d, col %d at %v, got: %d, %d at %v",
+				i, row, col, b.Grid.TileRect(row, col), char.Row, char.Col, char.Bounds)
+		}
+
+		if best := n.TileCandidates(b.Tiles[i], Alphabet, 1)[0]; char.Text != string(best.Char) {
+			t.Errorf("tile %d: expected %c, got: %s", i, best.Char, char.Text)
+		}
+
+		if len(char.Alternatives) != MaxAlternatives {
+			t.Errorf("tile %d: expected %d alternatives, got: %d", i, MaxAlternatives, len(char.Alternatives))
+		}
+	}
+}
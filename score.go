@@ -0,0 +1,282 @@
+package gocarina
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"sort"
+	"strconv"
+)
+
+const (
+	MinSaturation = 80  // pixels with a larger spread between their RGB channels (0..255) count as colored
+	MaxDigitGray  = 128 // score digits are grey pixels no brighter than this (0..255)
+	MinDigitArea  = 30  // smaller grey components in the header are ignored as noise
+)
+
+// Scoreboard is the game state shown in the header above the grid of a Letterpress screenshot.
+type Scoreboard struct {
+	Blue int   // number of tiles owned by the blue player
+	Red  int   // number of tiles owned by the red player
+	Turn Owner // the player whose turn it is
+}
+
+func (s *Scoreboard) String() string {
+	return fmt.Sprintf("blue: %d, red: %d, turn: %s", s.Blue, s.Red, s.Turn)
+}
+
+// scoreSide is one player's half of the header: their avatar, and the digits of their score beneath it.
+type scoreSide struct {
+	owner  Owner
+	avatar image.Rectangle
+	digits []*Tile
+}
+
+// ReadScore recognizes the players' scores in the header of the board image, along with whose turn it is.
+// The network must have been trained on digits, e.g. with the tiles from ReadKnownDigits (which lack 0, 3 and 6).
+func (b *Board) ReadScore(n *Network) (*Scoreboard, error) {
+	sides, turn, err := b.readHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Scoreboard{Turn: turn}
+
+	for _, side := range sides {
+		var digits []rune
+		for _, tile := range side.digits {
+			digits = append(digits, n.RecognizeTile(tile))
+		}
+
+		score, err := strconv.Atoi(string(digits))
+		if err != nil {
+			return nil, fmt.Errorf("unrecognized %s score: %q", side.owner, string(digits))
+		}
+
+		switch side.owner {
+		case Blue:
+			result.Blue = score
+		case Red:
+			result.Red = score
+		}
+	}
+
+	return result, nil
+}
+
+// ReadKnownDigits reads in the scores from the reference board images, and assigns the known-correct digits.
+// Between them, the boards show the digits 1, 2, 4, 5, 7, 8 and 9. There are no samples of 0, 3 or 6, so a network
+// trained on these tiles can't read scores containing them. The resulting map of tiles can be used to train a
+// network, alone or together with the letters from ReadKnownBoards.
+func ReadKnownDigits() map[rune]*Tile {
+	result := make(map[rune]*Tile)
+
+	scores := []struct {
+		file      string
+		blue, red string
+	}{
+		{"board-images/board1.png", "8", "9"},
+		{"board-images/board2.png", "5", "11"},
+		{"board-images/board3.png", "11", "12"},
+		{"board-images/board4.png", "15", "7"},
+		{"board-images/board5.png", "4", "5"},
+	}
+
+	for _, s := range scores {
+		b := ReadUnknownBoard(s.file)
+
+		sides, _, err := b.readHeader()
+		if err != nil {
+			log.Fatalf("%s: %s", s.file, err)
+		}
+
+		for _, side := range sides {
+			digits := []rune(s.blue)
+			if side.owner == Red {
+				digits = []rune(s.red)
+			}
+
+			if len(digits) != len(side.digits) {
+				log.Fatalf("%s: expected %d %s digits, found: %d", s.file, len(digits), side.owner, len(side.digits))
+			}
+
+			for i, tile := range side.digits {
+				tile.Letter = digits[i]
+				result[tile.Letter] = tile
+			}
+		}
+	}
+
+	return result
+}
+
+// readHeader finds the two players' avatars in the header above the grid, and the score digits beneath each. The
+// turn indicator is the colored triangle that appears beneath the score of the player whose turn it is.
+func (b *Board) readHeader() (sides []scoreSide, turn Owner, err error) {
+	bounds := b.img.Bounds()
+	header := image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, b.Grid.Origin.Y)
+
+	if header.Dy() < b.Grid.TileSize {
+		return nil, Unowned, errors.New("no header found above the grid")
+	}
+
+	sub := b.img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}).SubImage(header)
+
+	colored := LabelComponents(pixelMask(sub, isColored), EightConnected)
+	if len(colored.List) < 2 {
+		return nil, Unowned, errors.New("no player avatars found in the header")
+	}
+
+	// the avatars are the two largest colored regions, from left to right; the components are sorted in a copy, so
+	// that List stays in label order
+	bySize := append([]Component(nil), colored.List...)
+	sort.Slice(bySize, func(i, j int) bool { return bySize[i].Area > bySize[j].Area })
+	avatars := bySize[:2]
+	sort.Slice(avatars, func(i, j int) bool { return avatars[i].Bounds.Min.X < avatars[j].Bounds.Min.X })
+
+	for _, a := range avatars {
+		sides = append(sides, scoreSide{owner: ownerOf(sub, colored, a), avatar: a.Bounds})
+	}
+
+	// the turn indicator is the largest of the remaining colored regions below an avatar
+	var indicator *Component
+	for i, comp := range bySize[2:] {
+		if comp.Bounds.Min.Y < avatars[0].Bounds.Max.Y || sideOf(sides, comp) < 0 {
+			continue
+		}
+
+		if indicator == nil || comp.Area > indicator.Area {
+			indicator = &bySize[2+i]
+		}
+	}
+
+	if indicator == nil {
+		return nil, Unowned, errors.New("no turn indicator found in the header")
+	}
+	turn = ownerOf(sub, colored, *indicator)
+
+	// the digits are the grey regions beneath the avatars, and above the turn indicator
+	gray := LabelComponents(pixelMask(sub, isDarkGray), EightConnected)
+	byX := append([]Component(nil), gray.List...)
+	sort.Slice(byX, func(i, j int) bool { return byX[i].Bounds.Min.X < byX[j].Bounds.Min.X })
+
+	for _, comp := range byX {
+		if comp.Area < MinDigitArea || comp.Bounds.Min.Y < avatars[0].Bounds.Max.Y || comp.Bounds.Max.Y > indicator.Bounds.Min.Y {
+			continue
+		}
+
+		if i := sideOf(sides, comp); i >= 0 {
+			sides[i].digits = append(sides[i].digits, NewTile('?', componentImage(gray, comp)))
+		}
+	}
+
+	for _, side := range sides {
+		if len(side.digits) == 0 {
+			return nil, Unowned, fmt.Errorf("no score found for the %s player", side.owner)
+		}
+	}
+
+	return sides, turn, nil
+}
+
+// sideOf returns the index of the side whose avatar lies above the given component, or -1 if there is none.
+func sideOf(sides []scoreSide, comp Component) int {
+	for i, side := range sides {
+		if comp.CentroidX >= float64(side.avatar.Min.X) && comp.CentroidX < float64(side.avatar.Max.X) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ownerOf decides whether a colored component is blue or red, by the average color of its pixels.
+func ownerOf(img image.Image, comps *Components, comp Component) Owner {
+	var red, blue uint64
+
+	for y := comp.Bounds.Min.Y; y < comp.Bounds.Max.Y; y++ {
+		for x := comp.Bounds.Min.X; x < comp.Bounds.Max.X; x++ {
+			if comps.LabelAt(x, y) != comp.Label {
+				continue
+			}
+
+			r, _, b, _ := img.At(x, y).RGBA()
+			red += uint64(r)
+			blue += uint64(b)
+		}
+	}
+
+	if blue > red {
+		return Blue
+	}
+
+	return Red
+}
+
+// pixelMask returns a black & white image in which the pixels of img that satisfy pred are black.
+func pixelMask(img image.Image, pred func(c color.Color) bool) *image.Gray {
+	b := img.Bounds()
+	mask := image.NewGray(b)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if pred(img.At(x, y)) {
+				mask.SetGray(x, y, color.Gray{0})
+			} else {
+				mask.SetGray(x, y, color.Gray{0xff})
+			}
+		}
+	}
+
+	return mask
+}
+
+// componentImage returns a black & white image of just the given component, with a one pixel white margin.
+func componentImage(comps *Components, comp Component) image.Image {
+	r := comp.Bounds.Inset(-1)
+	img := image.NewGray(r)
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if comps.LabelAt(x, y) == comp.Label {
+				img.SetGray(x, y, color.Gray{0})
+			} else {
+				img.SetGray(x, y, color.Gray{0xff})
+			}
+		}
+	}
+
+	return img
+}
+
+// channels returns the smallest and largest of the RGB channels of c, on a 0..255 scale.
+func channels(c color.Color) (min, max uint32) {
+	r, g, b, _ := c.RGBA()
+	r, g, b = r>>8, g>>8, b>>8
+
+	min, max = r, r
+	for _, v := range []uint32{g, b} {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	return
+}
+
+func isColored(c color.Color) bool {
+	min, max := channels(c)
+	return max-min > MinSaturation
+}
+
+func isDarkGray(c color.Color) bool {
+	min, max := channels(c)
+	return max <= MaxDigitGray && max-min <= MinSaturation/2
+}
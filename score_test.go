@@ -0,0 +1,72 @@
+package gocarina
+
+import (
+	"testing"
+)
+
+func TestReadHeader(t *testing.T) {
+	var examples = []struct {
+		file   string
+		owners []Owner // from left to right
+		digits []int   // number of digits in each score
+		turn   Owner
+	}{
+		{"board-images/board1.png", []Owner{Blue, Red}, []int{1, 1}, Blue},
+		{"board-images/board2.png", []Owner{Red, Blue}, []int{2, 1}, Blue},
+		{"board-images/board3.png", []Owner{Blue, Red}, []int{2, 2}, Blue},
+		{"board-images/board4.png", []Owner{Red, Blue}, []int{1, 2}, Red},
+		{"board-images/board5.png", []Owner{Red, Blue}, []int{1, 1}, Red},
+	}
+
+	for _, tt := range examples {
+		sides, turn, err := ReadUnknownBoard(tt.file).readHeader()
+		if err != nil {
+			t.Errorf("%s: %s", tt.file, err)
+			continue
+		}
+
+		if turn != tt.turn {
+			t.Errorf("%s: expected turn %s, got: %s", tt.file, tt.turn, turn)
+		}
+
+		if len(sides) != len(tt.owners) {
+			t.Errorf("%s: expected %d sides, got: %d", tt.file, len(tt.owners), len(sides))
+			continue
+		}
+
+		for i, side := range sides {
+			if side.owner != tt.owners[i] || len(side.digits) != tt.digits[i] {
+				t.Errorf("%s: expected %s side with %d digits, got: %s with %d", tt.file, tt.owners[i], tt.digits[i], side.owner, len(side.digits))
+			}
+		}
+	}
+}
+
+func TestReadScore(t *testing.T) {
+	digits := ReadKnownDigits()
+
+	for _, d := range "1245789" {
+		if _, ok := digits[d]; !ok {
+			t.Errorf("expected a tile for digit %c", d)
+		}
+	}
+
+	n := NewNetwork(TileTargetWidth, TileTargetHeight)
+
+	// like training on letters, this occasionally fails to converge; see README
+	for i := 0; i < 500; i++ {
+		for _, tile := range digits {
+			n.TrainTile(tile)
+		}
+	}
+
+	score, err := ReadUnknownBoard("board-images/board4.png").ReadScore(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Scoreboard{Blue: 15, Red: 7, Turn: Red}
+	if *score != expected {
+		t.Errorf("expected %s, got: %s", &expected, score)
+	}
+}
@@ -0,0 +1,90 @@
+package gocarina
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ScrabbleRackSize   = 7        // tiles on a full rack
+	ScrabbleBingoBonus = 50       // bonus for playing every tile on a full rack in one go
+	ScrabbleBlank      = Wildcard // a blank tile on a rack; '_' and ' ' are accepted too
+)
+
+// RackWord is a word that can be played from a Scrabble rack.
+type RackWord struct {
+	Word   string
+	Blanks []int // indices into the letters of Word that are played with a blank tile
+	Score  int   // the face value of the tiles played, plus ScrabbleBingoBonus if they are the whole of a full rack
+}
+
+// ScrabbleFrom returns the words that can be played from the tiles of a Scrabble rack, which may include blanks.
+// Blanks are only used for letters the rack has run out of. Words are ordered by Score, then ByWordLength. Board
+// squares (and so premiums and hooks) are not considered.
+func (d *Dictionary) ScrabbleFrom(rack string) []RackWord {
+	rack = strings.NewReplacer("_", string(ScrabbleBlank), " ", string(ScrabbleBlank)).Replace(rack)
+	tiles := utf8.RuneCountInString(rack)
+	pool := newLetterPool(rack)
+
+	var words []string
+	found := make(map[string]RackWord)
+
+	d.search(context.Background(), pool, func(word []rune, wild []bool) bool {
+		w := RackWord{Word: string(word)}
+
+		for i, c := range word {
+			if wild[i] {
+				w.Blanks = append(w.Blanks, i)
+			} else {
+				w.Score += ScrabbleValues[c]
+			}
+		}
+
+		if tiles == ScrabbleRackSize && len(word) == tiles {
+			w.Score += ScrabbleBingoBonus
+		}
+
+		words = append(words, w.Word)
+		found[w.Word] = w
+
+		return true
+	})
+
+	return rankRackWords(words, found, byRackScore(found), 0)
+}
+
+// ScrabbleWordsFrom returns the words that can be played from the rack using the dictionary for DefaultLanguage, as
+// for Dictionary.ScrabbleFrom, but ordered by r, and at most limit of them. A nil r orders words by their Score.
+func ScrabbleWordsFrom(rack string, r Ranker, limit int) []RackWord {
+	var words []string
+	found := make(map[string]RackWord)
+
+	for _, w := range defaultLanguageDictionary().ScrabbleFrom(rack) {
+		words = append(words, w.Word)
+		found[w.Word] = w
+	}
+
+	if r == nil {
+		r = byRackScore(found)
+	}
+
+	return rankRackWords(words, found, r, limit)
+}
+
+// byRackScore ranks words by the Score they were found with.
+func byRackScore(found map[string]RackWord) Ranker {
+	return RankerFunc(func(word string) float64 {
+		return float64(found[word].Score)
+	})
+}
+
+// rankRackWords orders words by r, as for Rank, and returns the RackWord found for each.
+func rankRackWords(words []string, found map[string]RackWord, r Ranker, limit int) []RackWord {
+	var result []RackWord
+	for _, word := range Rank(words, r, limit) {
+		result = append(result, found[word])
+	}
+
+	return result
+}
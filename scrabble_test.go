@@ -0,0 +1,50 @@
+package gocarina
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScrabbleFrom(t *testing.T) {
+	d := NewDictionary([]string{"quiz", "quit", "suit", "it", "zit", "quartzy"})
+
+	expected := []RackWord{
+		{Word: "quiz", Blanks: []int{3}, Score: 12},
+		{Word: "quit", Score: 13},
+		{Word: "zit", Blanks: []int{0}, Score: 2},
+		{Word: "suit", Blanks: []int{0}, Score: 3},
+		{Word: "it", Score: 2},
+	}
+
+	actual := d.ScrabbleFrom("QUIT?")
+
+	// quit is worth more than quiz, as the z comes from a blank; the rest are ordered by score, then ByWordLength
+	expected = []RackWord{expected[1], expected[0], expected[3], expected[2], expected[4]}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %+v, got: %+v", expected, actual)
+	}
+
+	// a seven tile play earns the bingo bonus
+	bingo := d.ScrabbleFrom("quartz_")
+	if len(bingo) == 0 || bingo[0].Word != "quartzy" || bingo[0].Score != 1+10+1+1+1+10+ScrabbleBingoBonus {
+		t.Errorf("expected a bingo for %q, got: %+v", "quartzy", bingo)
+	}
+
+	if words := d.ScrabbleFrom("xx"); len(words) != 0 {
+		t.Errorf("expected no words, got: %+v", words)
+	}
+}
+
+func TestScrabbleWordsFrom(t *testing.T) {
+	words := ScrabbleWordsFrom("quiz", nil, 1)
+	if len(words) != 1 || words[0].Word != "quiz" || words[0].Score != 22 {
+		t.Errorf("expected just %q, got: %+v", "quiz", words)
+	}
+
+	words = ScrabbleWordsFrom("a?", LengthRanker, 0)
+	for _, w := range words {
+		if len(w.Word) > 2 {
+			t.Errorf("expected no words longer than the rack, got: %q", w.Word)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package gocarina
+
+import (
+	"image"
+	"math"
+)
+
+const (
+	MinCutWidthPercent   = 0.5  // glyphs narrower than this fraction of the line's height are never cut
+	MaxCutDensityPercent = 0.15 // columns with no more ink than this fraction of the line's height may be cut
+	MaxGlyphWidthPercent = 1.5  // pieces are not recombined into glyphs wider than this fraction of the line's height
+	MaxGlyphPieces       = 3    // the most pieces a glyph may be recombined from
+	MinSegmentConfidence = 0.5  // glyphs that are cut or rejoined must be read with at least this confidence
+)
+
+// piece is part of a word, from which glyphs are recombined.
+type piece struct {
+	Bounds image.Rectangle
+	Cut    bool // cut from the same glyph as the piece before it, rather than separated from it by blank columns
+}
+
+// segmentWord finds the glyphs of word, and what the network reads each of them as. Splitting only at blank
+// columns, as lineGlyphs does, leaves touching glyphs (such as "rn") as one, and breaks faint glyphs in two. So
+// the word is first over-segmented into pieces, cutting glyphs at the thinnest points between their strokes; then
+// the pieces are recombined into whichever glyphs the network is most confident of.
+func segmentWord(n *Network, bw image.Image, word WordLayout, height int) ([]image.Rectangle, []Candidate) {
+	var pieces []piece
+	for _, g := range word.Glyphs {
+		for i, r := range cutGlyph(bw, g, height) {
+			pieces = append(pieces, piece{Bounds: r, Cut: i > 0})
+		}
+	}
+
+	var glyphs []image.Rectangle
+	var candidates []Candidate
+
+	maxWidth := int(MaxGlyphWidthPercent * float64(height))
+	bestSegmentation(pieces, maxWidth, func(r image.Rectangle) Candidate {
+		return recognizeGlyph(n, bw, r)
+	}, func(r image.Rectangle, c Candidate) {
+		glyphs = append(glyphs, r)
+		candidates = append(candidates, c)
+	})
+
+	return glyphs, candidates
+}
+
+// recognizeGlyph returns what the network reads the glyph within r of bw as.
+func recognizeGlyph(n *Network, bw image.Image, r image.Rectangle) Candidate {
+	return n.BestCandidate(reduceGlyph(bw, r, n.Normalization))
+}
+
+// reduceGlyph scales the glyph within r of bw down to the network's inputs, as Tile.reduce does for a tile. Unlike a
+// tile, the glyph is bounded by r itself, so that pieces rejoined into a glyph aren't cut back to the largest of them.
+func reduceGlyph(bw image.Image, r image.Rectangle, mode Normalization) image.Image {
+	glyph := glyphImage(bw, r).(*image.Gray).SubImage(r)
+	target := image.Rect(0, 0, TileTargetWidth, TileTargetHeight)
+
+	if mode == NormalizeAspect {
+		return ScaleToFit(glyph, target)
+	}
+
+	return Scale(glyph, target)
+}
+
+// cutGlyph splits the glyph within r of bw into pieces at each column that is a local minimum of ink, and thin
+// enough to be where two touching glyphs meet. Each piece is bounded tightly around its ink.
+func cutGlyph(bw image.Image, r image.Rectangle, height int) []image.Rectangle {
+	if float64(r.Dx()) < MinCutWidthPercent*float64(height) {
+		return []image.Rectangle{r}
+	}
+
+	density := make([]int, r.Dx())
+	for x := r.Min.X; x < r.Max.X; x++ {
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			if IsBlack(bw.At(x, y)) {
+				density[x-r.Min.X]++
+			}
+		}
+	}
+
+	// keep the pieces at least a few pixels wide, so that strokes aren't shaved off their glyphs
+	minWidth := height / 10
+	if minWidth < 1 {
+		minWidth = 1
+	}
+
+	var result []image.Rectangle
+	start := r.Min.X
+	for i := minWidth; i < len(density)-minWidth; i++ {
+		x := r.Min.X + i
+		thin := float64(density[i]) <= MaxCutDensityPercent*float64(height)
+
+		// on a plateau of equal densities, cut at its first column
+		if thin && density[i] < density[i-1] && density[i] <= density[i+1] && x-start >= minWidth {
+			result = append(result, inkBounds(bw, image.Rect(start, r.Min.Y, x, r.Max.Y)))
+			start = x
+		}
+	}
+	result = append(result, inkBounds(bw, image.Rect(start, r.Min.Y, r.Max.X, r.Max.Y)))
+
+	return result
+}
+
+// bestSegmentation recombines the pieces of a word, left to right, into the glyphs that score best, and calls
+// found with each of them, left to right. Each glyph is a run of up to MaxGlyphPieces consecutive pieces, no wider
+// than maxWidth unless it is a single piece, or those between blank columns.
+//
+// This is a Viterbi search: the score of a segmentation is the product of the confidence in each of its glyphs,
+// raised to the number of pieces in the glyph, so that segmentations with fewer glyphs aren't favoured just because
+// they multiply fewer confidences together. Glyphs other than those between blank columns are only considered if
+// the network reads them with at least MinSegmentConfidence, so the network must be sure of any cut or rejoin.
+func bestSegmentation(pieces []piece, maxWidth int, score func(image.Rectangle) Candidate,
+	found func(image.Rectangle, Candidate)) {
+
+	// best[j] is the log score of the best segmentation of the first j pieces, whose last glyph starts at piece
+	// from[j], and is read as read[j]
+	best := make([]float64, len(pieces)+1)
+	from := make([]int, len(pieces)+1)
+	read := make([]Candidate, len(pieces)+1)
+	bounds := make([]image.Rectangle, len(pieces)+1)
+
+	for j := 1; j <= len(pieces); j++ {
+		best[j] = math.Inf(-1)
+
+		consider := func(i int, r image.Rectangle, c Candidate) {
+			s := best[i] + float64(j-i)*math.Log(math.Max(c.Confidence, math.SmallestNonzeroFloat64))
+			if s > best[j] {
+				best[j], from[j], read[j], bounds[j] = s, i, c, r
+			}
+		}
+
+		r := pieces[j-1].Bounds
+		endsBlank := j == len(pieces) || !pieces[j].Cut
+		bridges, triedOriginal := false, false
+		for i := j - 1; i >= 0 && j-i <= MaxGlyphPieces; i-- {
+			r = r.Union(pieces[i].Bounds)
+			if i < j-1 && r.Dx() > maxWidth {
+				break
+			}
+
+			// only glyphs found between blank columns are considered whatever the network reads them as
+			bridges = bridges || (i < j-1 && !pieces[i+1].Cut)
+			original := endsBlank && !bridges && (i == 0 || !pieces[i].Cut)
+			triedOriginal = triedOriginal || original
+
+			if c := score(r); original || c.Confidence >= MinSegmentConfidence {
+				consider(i, r, c)
+			}
+		}
+
+		// a glyph between blank columns is always a way to end here, however wide it is or many pieces it was cut
+		// into, so that every word can be read somehow
+		if endsBlank && !triedOriginal {
+			i := j - 1
+			r := pieces[i].Bounds
+			for i > 0 && pieces[i].Cut {
+				i--
+				r = r.Union(pieces[i].Bounds)
+			}
+
+			consider(i, r, score(r))
+		}
+	}
+
+	var ends []int
+	for j := len(pieces); j > 0; j = from[j] {
+		ends = append(ends, j)
+	}
+
+	for k := len(ends) - 1; k >= 0; k-- {
+		found(bounds[ends[k]], read[ends[k]])
+	}
+}
+
+// inkBounds returns the smallest rectangle within r that holds all of the black pixels of bw in it.
+func inkBounds(bw image.Image, r image.Rectangle) image.Rectangle {
+	var result image.Rectangle
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if IsBlack(bw.At(x, y)) {
+				result = result.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,120 @@
+package gocarina
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestCutGlyph(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.ZP, draw.Src)
+
+	// two glyphs touching along a thin stroke at the bottom, like "rn"
+	for _, r := range []image.Rectangle{image.Rect(2, 0, 8, 20), image.Rect(8, 18, 10, 20), image.Rect(10, 0, 16, 20)} {
+		draw.Draw(img, r, &image.Uniform{color.Black}, image.ZP, draw.Src)
+	}
+
+	pieces := cutGlyph(img, image.Rect(2, 0, 16, 20), 20)
+	expected := []image.Rectangle{image.Rect(2, 0, 8, 20), image.Rect(8, 0, 16, 20)}
+
+	if len(pieces) != len(expected) {
+		t.Fatalf("expected %v, got: %v", expected, pieces)
+	}
+
+	for i := range pieces {
+		if pieces[i] != expected[i] {
+			t.Errorf("expected %v, got: %v", expected, pieces)
+		}
+	}
+
+	// narrow glyphs are left alone
+	if pieces := cutGlyph(img, image.Rect(2, 0, 16, 20), 40); len(pieces) != 1 {
+		t.Errorf("expected 1 piece, got: %v", pieces)
+	}
+}
+
+func TestBestSegmentation(t *testing.T) {
+	// the "network" reads glyphs of about the right width as 'x', and anything else as '?'
+	reader := func(confidence float64) func(image.Rectangle) Candidate {
+		return func(r image.Rectangle) Candidate {
+			if r.Dx() >= 5 && r.Dx() <= 7 {
+				return Candidate{'x', confidence}
+			}
+
+			return Candidate{'?', 0.1}
+		}
+	}
+
+	examples := []struct {
+		name       string
+		pieces     []piece
+		confidence float64
+		expected   []image.Rectangle
+	}{
+		{"touching", []piece{{image.Rect(0, 0, 6, 10), false}, {image.Rect(6, 0, 12, 10), true}}, 0.9,
+			[]image.Rectangle{image.Rect(0, 0, 6, 10), image.Rect(6, 0, 12, 10)}},
+		{"broken", []piece{{image.Rect(0, 0, 3, 10), false}, {image.Rect(4, 0, 6, 10), false}}, 0.9,
+			[]image.Rectangle{image.Rect(0, 0, 6, 10)}},
+		{"unsure", []piece{{image.Rect(0, 0, 6, 10), false}, {image.Rect(6, 0, 12, 10), true}}, 0.3,
+			[]image.Rectangle{image.Rect(0, 0, 12, 10)}},
+		{"separate", []piece{{image.Rect(0, 0, 6, 10), false}, {image.Rect(8, 0, 14, 10), false}}, 0.9,
+			[]image.Rectangle{image.Rect(0, 0, 6, 10), image.Rect(8, 0, 14, 10)}},
+		{"too many pieces", []piece{{image.Rect(0, 0, 3, 10), false}, {image.Rect(3, 0, 6, 10), true},
+			{image.Rect(6, 0, 9, 10), true}, {image.Rect(9, 0, 12, 10), true}}, 0.1,
+			[]image.Rectangle{image.Rect(0, 0, 12, 10)}},
+		{"too wide", []piece{{image.Rect(0, 0, 15, 10), false}, {image.Rect(15, 0, 30, 10), true}}, 0.1,
+			[]image.Rectangle{image.Rect(0, 0, 30, 10)}},
+	}
+
+	for _, ex := range examples {
+		var got []image.Rectangle
+		bestSegmentation(ex.pieces, 20, reader(ex.confidence), func(r image.Rectangle, _ Candidate) {
+			got = append(got, r)
+		})
+
+		if len(got) != len(ex.expected) {
+			t.Errorf("%s: expected %v, got: %v", ex.name, ex.expected, got)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != ex.expected[i] {
+				t.Errorf("%s: expected %v, got: %v", ex.name, ex.expected, got)
+			}
+		}
+	}
+}
+
+func TestRecognizeGlyph(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 30, 40))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.ZP, draw.Src)
+
+	// a glyph broken in two by a blank column: a "[" with a short stroke to its right
+	strokes := []image.Rectangle{image.Rect(5, 5, 8, 35), image.Rect(5, 5, 12, 8), image.Rect(5, 32, 12, 35),
+		image.Rect(15, 5, 18, 20)}
+	for _, r := range strokes {
+		draw.Draw(img, r, &image.Uniform{color.Black}, image.ZP, draw.Src)
+	}
+	left, glyph := image.Rect(5, 5, 12, 35), image.Rect(5, 5, 18, 35)
+
+	for _, mode := range []Normalization{NormalizeStretch, NormalizeAspect} {
+		n := NewNetwork(TileTargetWidth, TileTargetHeight)
+		n.Normalization = mode
+
+		// the network can only tell the whole glyph from its larger piece if it sees all of the glyph
+		for i := 0; i < 1000; i++ {
+			n.Train(reduceGlyph(img, glyph, mode), 'H')
+			n.Train(reduceGlyph(img, left, mode), 'I')
+		}
+
+		if c := recognizeGlyph(n, img, glyph); c.Char != 'H' {
+			t.Errorf("mode %d: expected %c, got: %c", mode, 'H', c.Char)
+		}
+
+		if c := recognizeGlyph(n, img, left); c.Char != 'I' {
+			t.Errorf("mode %d: expected %c, got: %c", mode, 'I', c.Char)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package gocarina
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Session follows a single game of Letterpress from one screenshot to the next, keeping track of the words that
+// have been played. Letterpress forbids replaying a word, and also forbids playing a prefix of a word that has
+// already been played (e.g. "cat" after "cats"; though "cats" after "cat" is fine).
+type Session struct {
+	Played []string // words played so far by either player, lower-cased, in order of play
+}
+
+func NewSession() *Session {
+	return &Session{}
+}
+
+// Allowed returns true if word may still be played: it must not have been played already, nor be a prefix of any
+// word that has.
+func (s *Session) Allowed(word string) bool {
+	word = strings.ToLower(word)
+
+	for _, played := range s.Played {
+		if strings.HasPrefix(played, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Record adds word to the words played, or returns an error if the rules don't allow it to be played.
+func (s *Session) Record(word string) error {
+	if !s.Allowed(word) {
+		return fmt.Errorf("%q has already been played, or is a prefix of a word that has", word)
+	}
+
+	s.Played = append(s.Played, strings.ToLower(word))
+
+	return nil
+}
+
+// Copy returns a copy of the session, which can be recorded into without affecting the original.
+func (s *Session) Copy() *Session {
+	return &Session{Played: append([]string(nil), s.Played...)}
+}
+
+// Filter returns the words that may still be played, in their original order.
+func (s *Session) Filter(words []string) []string {
+	var result []string
+	for _, word := range words {
+		if s.Allowed(word) {
+			result = append(result, word)
+		}
+	}
+
+	return result
+}
+
+// FilterPlays returns the plays whose words may still be played, in their original order.
+func (s *Session) FilterPlays(plays []Play) []Play {
+	var result []Play
+	for _, p := range plays {
+		if s.Allowed(p.Word) {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+func (s *Session) Save(filePath string) error {
+	buf := new(bytes.Buffer)
+	encoder := gob.NewEncoder(buf)
+
+	err := encoder.Encode(s)
+	if err != nil {
+		return fmt.Errorf("error encoding session: %s", err)
+	}
+
+	err = ioutil.WriteFile(filePath, buf.Bytes(), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing session to file: %s", err)
+	}
+
+	return nil
+}
+
+func RestoreSession(filePath string) (*Session, error) {
+	b, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading session file: %s", err)
+	}
+
+	decoder := gob.NewDecoder(bytes.NewBuffer(b))
+
+	var result Session
+	err = decoder.Decode(&result)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding session: %s", err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,88 @@
+package gocarina
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSessionAllowed(t *testing.T) {
+	s := NewSession()
+	if err := s.Record("Cats"); err != nil {
+		t.Fatal(err)
+	}
+
+	examples := []struct {
+		word    string
+		allowed bool
+	}{
+		{"cats", false}, // already played
+		{"CATS", false},
+		{"cat", false}, // prefix of a played word
+		{"c", false},
+		{"catsup", true}, // played word is a prefix of it, which is fine
+		{"scat", true},
+		{"at", true},
+	}
+
+	for _, ex := range examples {
+		if actual := s.Allowed(ex.word); actual != ex.allowed {
+			t.Errorf("%q: expected allowed: %t, got: %t", ex.word, ex.allowed, actual)
+		}
+	}
+
+	if err := s.Record("cat"); err == nil {
+		t.Errorf("expected an error recording a prefix of %q", "cats")
+	}
+
+	expected := []string{"catsup", "at"}
+	actual := s.Filter([]string{"cats", "catsup", "cat", "at"})
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestSolverSkipsPlayedWords(t *testing.T) {
+	b := NewBoard(1, 4, "CATS", "....")
+
+	s := NewSolver(b, Blue)
+	s.Words = []string{"cats", "cat", "scat", "at"}
+	s.Session = NewSession()
+	s.Session.Record("cats")
+
+	for _, p := range s.Plays() {
+		if p.Word == "cats" || p.Word == "cat" {
+			t.Errorf("expected %q to be filtered out", p.Word)
+		}
+	}
+
+	if len(s.Plays()) != 2 {
+		t.Errorf("expected 2 plays, got: %+v", s.Plays())
+	}
+}
+
+func TestSessionSaveRestore(t *testing.T) {
+	s := NewSession()
+	s.Record("cats")
+	s.Record("dog")
+
+	f, err := ioutil.TempFile("", "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := s.Save(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := RestoreSession(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(s, restored) {
+		t.Fatalf("expected: %+v, got %+v", s, restored)
+	}
+}
@@ -1,61 +1,62 @@
 package gocarina
 
 import (
-	"bufio"
-	"log"
-	"os"
 	"sort"
-	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-// WordsFrom returns a slice of dictionary words that can be constructed from the given chars.
+// WordsFrom returns a slice of words from the dictionary for DefaultLanguage that can be constructed from the given
+// chars.
 func WordsFrom(chars string) []string {
-	chars = strings.ToLower(chars)
-
-	file, err := os.Open("words-en.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer file.Close()
-
-	var result []string
-
-	// iterate every word in the file. NB: words are already lower-cased in the file.
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		word := scanner.Text()
-		if CanMakeWordFrom(word, chars) {
-			result = append(result, word)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
-	}
-
-	sort.Sort(ByWordLength(result))
-
-	return result
+	return defaultLanguageDictionary().WordsFrom(chars)
 }
 
 // CanMakeWordFrom returns true if the characters from 'chars' can be re-ordered to form 'word', else false.
 // Leftover letters are OK, but individual letters cannot be re-used. If a given letter is needed multiple times
-// (e.g. 'door' needs two o's), then the letter must appear multiple times in 'chars'.
+// (e.g. 'door' needs two o's), then the letter must appear multiple times in 'chars'. A Wildcard in 'chars' can
+// stand for any one letter.
 func CanMakeWordFrom(word string, chars string) bool {
+	_, ok := SubstitutionsFor(word, chars)
+	return ok
+}
+
+// Substitution records a Wildcard standing in for a letter.
+type Substitution struct {
+	Index  int  // where the wildcard is: an index into the chars searched, or into Board.Tiles
+	Letter rune // the letter it stands for
+}
+
+// SubstitutionsFor is like CanMakeWordFrom, but also returns the wildcards in chars that word relies on, and the
+// letters they stand for. Wildcards are only used once chars has run out of a letter.
+func SubstitutionsFor(word string, chars string) ([]Substitution, bool) {
 	pool := []rune(chars)
+	var wanted []rune
 
 	// iterate every char in word, and take them one at a time from pool
 	for _, c := range word {
 		var ok bool
 
 		if pool, ok = takeOne(pool, c); !ok {
-			// couldn't find c in pool
-			return false
+			// couldn't find c in pool, so it will need a wildcard
+			wanted = append(wanted, c)
 		}
 	}
 
-	// found every letter
-	return true
+	var result []Substitution
+	for i, c := range []rune(chars) {
+		if len(wanted) == 0 {
+			break
+		}
+
+		if c == Wildcard {
+			result = append(result, Substitution{Index: i, Letter: wanted[0]})
+			wanted = wanted[1:]
+		}
+	}
+
+	// found every letter, or a wildcard for it
+	return result, len(wanted) == 0
 }
 
 // takeOne will remove one instance of the given char from pool. It returns the (possibly modified) slice,
@@ -78,31 +79,284 @@ type ByWordLength []string
 func (w ByWordLength) Len() int      { return len(w) }
 func (w ByWordLength) Swap(i, j int) { w[i], w[j] = w[j], w[i] }
 func (w ByWordLength) Less(i, j int) bool {
-	ri := []rune(w[i])
-	rj := []rune(w[j])
+	li := utf8.RuneCountInString(w[i])
+	lj := utf8.RuneCountInString(w[j])
 
 	// first sort on word-length, descending
-	if len(ri) > len(rj) {
-		return true
+	if li != lj {
+		return li > lj
+	}
+
+	// lengths are equal, so sort secondarily by alphabet. Comparing UTF-8 strings byte by byte gives the same
+	// order as comparing them rune by rune, without having to convert them to []rune.
+	return w[i] < w[j]
+}
+
+// Play is a word on a Letterpress board, together with the tiles chosen to spell it and what they would win.
+type Play struct {
+	Word     string
+	Tiles    []int // indices into Board.Tiles, one per letter of Word
+	Gained   int   // unowned tiles claimed
+	Captured int   // tiles taken from the opponent
+	Defended int   // tiles of the player's that become locked as a result
+
+	Substitutions []Substitution // the Wildcard tiles used, and the letters they are played as
+}
+
+// Score returns the change in the player's lead over the opponent: each captured tile counts twice, as the
+// opponent loses it too.
+func (p Play) Score() int {
+	return p.Gained + 2*p.Captured
+}
+
+// Solver finds plays on a Letterpress board on behalf of one of the players.
+type Solver struct {
+	Board      *Board
+	Player     Owner
+	Words      []string    // candidate words; if nil, every word in Dictionary that can be formed from the board's letters
+	Dictionary *Dictionary // defaults to the dictionary for DefaultLanguage
+	Session    *Session    // if set, words already played (or prefixes of them) are skipped
+}
+
+func NewSolver(b *Board, player Owner) *Solver {
+	return &Solver{Board: b, Player: player}
+}
+
+// Plays returns the best play for every candidate word that can be formed from the letters on the board.
+// Plays are ordered by Score, then by the number of tiles defended, then longest word first.
+func (s *Solver) Plays() []Play {
+	if s.Words == nil {
+		d := s.Dictionary
+		if d == nil {
+			d = defaultLanguageDictionary()
+		}
+
+		s.Words = d.wordsFrom(s.Board.letterPool())
 	}
 
-	if len(ri) < len(rj) {
-		return false
+	words := s.Words
+	if s.Session != nil {
+		words = s.Session.Filter(words)
+	}
+
+	var result []Play
+	for _, word := range words {
+		if p, ok := s.Play(word); ok {
+			result = append(result, p)
+		}
+	}
+
+	sort.Sort(ByPlayScore(result))
+
+	return result
+}
+
+// Play chooses the tiles with which to spell word: tiles that can be captured from the opponent first, then unowned
+// tiles, then any others. It returns false if the board lacks the letters for word.
+func (s *Solver) Play(word string) (Play, bool) {
+	return s.PlayUsing(word, nil)
+}
+
+// PlayUsing is like Play, but the play must use the given tiles; the solver only chooses the rest. It returns false
+// if word can't be spelled using all of them.
+//
+// Wildcard tiles are only used for letters that no other tile is left for, and are listed in the play's
+// Substitutions.
+func (s *Solver) PlayUsing(word string, tiles []int) (Play, bool) {
+	b := s.Board
+	letters := []rune(word)
+	p := Play{Word: word}
+
+	// the tiles to choose from, in order of preference: the required ones, then the others by rank, with wildcards
+	// last, as they're only used for letters that no other tile is left for
+	order := append([]int(nil), tiles...)
+	used := make([]bool, len(b.Tiles))
+	for _, t := range tiles {
+		if t < 0 || t >= len(b.Tiles) || used[t] {
+			return Play{}, false
+		}
+		used[t] = true
 	}
 
-	// lengths are equal, so sort secondarily by alphabet
-	for i, _ := range ri {
-		if ri[i] == rj[i] {
+	var others, wilds []int
+	for j, tile := range b.Tiles {
+		if used[j] {
 			continue
 		}
 
-		if ri[i] < rj[i] {
-			return true
+		if tile.Letter == Wildcard {
+			wilds = append(wilds, j)
+		} else {
+			others = append(others, j)
 		}
+	}
+
+	sort.SliceStable(others, func(x, y int) bool { return s.rank(others[x]) > s.rank(others[y]) })
+	sort.SliceStable(wilds, func(x, y int) bool { return s.rank(wilds[x]) > s.rank(wilds[y]) })
+	order = append(append(order, others...), wilds...)
+
+	// the required tiles are matched to letters first; matching the rest of the letters may move them to other
+	// letters, but never leaves one of them out
+	required := matchWildcards(len(tiles), len(letters), func(t, i int) bool {
+		return b.Tiles[tiles[t]].Matches(letters[i])
+	})
+
+	if required == nil {
+		return Play{}, false
+	}
+
+	letterFor := make([]int, len(order))
+	for j := range letterFor {
+		letterFor[j] = -1
+	}
+	for t, i := range required {
+		letterFor[t] = i
+	}
+
+	assigned := extendMatching(len(letters), len(order), letterFor, func(i, j int) bool {
+		return b.Tiles[order[j]].Matches(letters[i])
+	})
+
+	if assigned == nil {
+		return Play{}, false
+	}
+
+	for _, j := range assigned {
+		p.Tiles = append(p.Tiles, order[j])
+	}
+
+	for i, t := range p.Tiles {
+		if b.Tiles[t].Letter == Wildcard {
+			p.Substitutions = append(p.Substitutions, Substitution{Index: t, Letter: letters[i]})
+		}
+	}
+
+	after := b.Apply(p, s.Player)
+
+	for i, tile := range b.Tiles {
+		if after.Tiles[i].Owner == s.Player && tile.Owner != s.Player {
+			if tile.Owner == Unowned {
+				p.Gained++
+			} else {
+				p.Captured++
+			}
+		}
+
+		if after.Tiles[i].Owner == s.Player && after.Tiles[i].Locked && !(tile.Owner == s.Player && tile.Locked) {
+			p.Defended++
+		}
+	}
+
+	return p, true
+}
+
+// rank orders the tiles the solver would rather use: capturable opponent tiles, then unowned tiles, then any others.
+// Ties go to tiles with more neighbours owned by the player, as those are more likely to become defended.
+func (s *Solver) rank(i int) int {
+	tile := s.Board.Tiles[i]
+
+	rank := 0
+	switch {
+	case tile.Owner == s.Player.Opponent() && !tile.Locked:
+		rank = 2
+	case tile.Owner == Unowned:
+		rank = 1
+	}
+
+	friendly := 0
+	for _, n := range s.Board.Neighbours(i) {
+		if s.Board.Tiles[n].Owner == s.Player {
+			friendly++
+		}
+	}
+
+	return rank*10 + friendly
+}
+
+// Letters returns the letters of the board's tiles, in row-major order.
+func (b *Board) Letters() string {
+	var letters []rune
+	for _, tile := range b.Tiles {
+		letters = append(letters, tile.Letter)
+	}
+
+	return string(letters)
+}
+
+// letterPool returns the letters of the board's tiles, as a pool for a Dictionary search. Wildcard tiles may stand
+// for any of their Candidates.
+func (b *Board) letterPool() *letterPool {
+	p := newLetterPool("")
+	for _, tile := range b.Tiles {
+		if tile.Letter == Wildcard {
+			p.addWild(string(tile.Candidates))
+		} else {
+			p.put(unicode.ToLower(tile.Letter))
+		}
+	}
+
+	return p
+}
+
+// Neighbours returns the indices of the tiles directly above, below, left and right of the tile at index i.
+func (b *Board) Neighbours(i int) []int {
+	row, col := b.Position(i)
+
+	var result []int
+	for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		r, c := row+d[0], col+d[1]
+		if r >= 0 && r < b.Rows() && c >= 0 && c < b.Cols() {
+			result = append(result, b.Index(r, c))
+		}
+	}
+
+	return result
+}
+
+// Apply returns a copy of the board with the given play made by player. Each tile used is claimed by the player,
+// unless it is locked by the opponent. A tile is locked when all of its neighbours belong to the same player as it.
+func (b *Board) Apply(p Play, player Owner) *Board {
+	result := *b
+	result.Tiles = make([]*Tile, len(b.Tiles))
+
+	for i, tile := range b.Tiles {
+		t := *tile
+		result.Tiles[i] = &t
+	}
+
+	for _, i := range p.Tiles {
+		tile := result.Tiles[i]
+		if !(tile.Owner == player.Opponent() && tile.Locked) {
+			tile.Owner = player
+		}
+	}
+
+	for i, tile := range result.Tiles {
+		tile.Locked = tile.Owner != Unowned
+
+		for _, n := range result.Neighbours(i) {
+			if result.Tiles[n].Owner != tile.Owner {
+				tile.Locked = false
+			}
+		}
+	}
+
+	return &result
+}
+
+// Sort by Play.Score descending, then by tiles defended descending, then as ByWordLength.
+type ByPlayScore []Play
+
+func (p ByPlayScore) Len() int      { return len(p) }
+func (p ByPlayScore) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p ByPlayScore) Less(i, j int) bool {
+	if p[i].Score() != p[j].Score() {
+		return p[i].Score() > p[j].Score()
+	}
 
-		return false
+	if p[i].Defended != p[j].Defended {
+		return p[i].Defended > p[j].Defended
 	}
 
-	// if we get here, all chars are equal, but "x" is still not < "x", so return false
-	return false
+	return ByWordLength{p[i].Word, p[j].Word}.Less(0, 1)
 }
@@ -17,6 +17,9 @@ func TestCanMakeWordFrom(t *testing.T) {
 		{"brunch", "launch", false},
 		{"aaa", "aaa", true},
 		{"aaaa", "aaa", false},
+		{"lunch", "la?nch", true},
+		{"aaaa", "a??a", true},
+		{"brunch", "la?nch", false},
 	}
 
 	for _, tt := range examples {
@@ -63,3 +66,121 @@ func TestSortByWordLength(t *testing.T) {
 		}
 	}
 }
+
+func TestSolverPlay(t *testing.T) {
+	b := NewBoard(2, 3,
+		"CAT"+
+			"SAT",
+		".rR"+
+			"brR")
+
+	s := NewSolver(b, Blue)
+
+	actual, ok := s.Play("cat")
+	if !ok {
+		t.Fatalf("expected to be able to play %q", "cat")
+	}
+
+	// the C is unowned, either A can be captured, but both Ts are locked by red
+	expected := Play{Word: "cat", Tiles: []int{0, 4, 2}, Gained: 1, Captured: 1, Defended: 1}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected: %+v, got: %+v", expected, actual)
+	}
+
+	if _, ok := s.Play("catch"); ok {
+		t.Errorf("expected not to be able to play %q", "catch")
+	}
+
+	after := b.Apply(actual, Blue)
+	for i, owner := range []Owner{Blue, Red, Red, Blue, Blue, Red} {
+		if after.Tiles[i].Owner != owner {
+			t.Errorf("tile %d: expected owner %s, got: %s", i, owner, after.Tiles[i].Owner)
+		}
+	}
+
+	if b.Tiles[0].Owner != Unowned {
+		t.Errorf("expected Apply to leave the original board alone")
+	}
+}
+
+func TestSolverPlays(t *testing.T) {
+	b := NewBoard(2, 3, "CATSAT", ".rRbrR")
+
+	plays := NewSolver(b, Blue).Plays()
+	if len(plays) == 0 {
+		t.Fatalf("expected some plays")
+	}
+
+	for i := 1; i < len(plays); i++ {
+		if ByPlayScore(plays).Less(i, i-1) {
+			t.Errorf("expected %+v to come before %+v", plays[i], plays[i-1])
+		}
+	}
+}
+
+func TestSubstitutionsFor(t *testing.T) {
+	var examples = []struct {
+		word     string
+		chars    string
+		expected []Substitution
+		ok       bool
+	}{
+		{"lunch", "launch", nil, true},
+		{"lunch", "la?nch", []Substitution{{2, 'u'}}, true},
+		{"cab", "?a?c", []Substitution{{0, 'b'}}, true},
+		{"abba", "?a??", []Substitution{{0, 'b'}, {2, 'b'}, {3, 'a'}}, true},
+		{"abba", "?a?", nil, false},
+	}
+
+	for _, ex := range examples {
+		actual, ok := SubstitutionsFor(ex.word, ex.chars)
+		if ok != ex.ok || (ok && !reflect.DeepEqual(ex.expected, actual)) {
+			t.Errorf("%q from %q: expected %v, %t, got: %v, %t", ex.word, ex.chars, ex.expected, ex.ok, actual, ok)
+		}
+	}
+}
+
+func TestSolverWildcards(t *testing.T) {
+	b := NewBoard(2, 3,
+		"CA?"+
+			"?AT",
+		"......")
+
+	// the first wildcard may only be an S or an O
+	b.Tiles[2].Candidates = []rune{'S', 'O'}
+
+	s := NewSolver(b, Blue)
+	s.Words = []string{"cats", "scab", "that", "chat", "cat"}
+
+	// the real tiles are used where possible
+	p, ok := s.Play("cat")
+	if !ok || p.Substitutions != nil {
+		t.Errorf("expected %q to be played without wildcards, got: %+v", "cat", p)
+	}
+
+	// scab needs both wildcards, and the S must be the restricted one
+	p, ok = s.Play("scab")
+	expected := []Substitution{{2, 's'}, {3, 'b'}}
+	if !ok || !reflect.DeepEqual(expected, p.Substitutions) {
+		t.Errorf("expected substitutions %v, got: %+v", expected, p)
+	}
+
+	if p.Tiles[0] != 2 || p.Tiles[3] != 3 {
+		t.Errorf("expected the S on tile 2 and the B on tile 3, got: %v", p.Tiles)
+	}
+
+	var words []string
+	for _, p := range s.Plays() {
+		words = append(words, p.Word)
+	}
+	sort.Strings(words)
+
+	// that needs an H and a second T, but the restricted wildcard can be neither
+	if expected := []string{"cat", "cats", "chat", "scab"}; !reflect.DeepEqual(expected, words) {
+		t.Errorf("expected: %v, got: %v", expected, words)
+	}
+
+	if _, ok := s.Play("chub"); ok {
+		t.Errorf("expected %q to need too many wildcards", "chub")
+	}
+}
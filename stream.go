@@ -0,0 +1,62 @@
+package gocarina
+
+import (
+	"context"
+)
+
+// SearchFrom streams the words that can be constructed from the given chars, calling fn with each as soon as it is
+// found, rather than collecting and sorting them first as WordsFrom does. So words arrive in alphabetical order,
+// not ByWordLength.
+//
+// The search stops after max words (or never, if max is 0), when fn returns false, or when ctx is done, e.g. because
+// the board has changed and the results are no longer wanted. Only in the last case is an error returned: ctx.Err().
+func (d *Dictionary) SearchFrom(ctx context.Context, chars string, max int, fn func(word string) bool) error {
+	found := 0
+
+	return d.search(ctx, newLetterPool(chars), func(word []rune, _ []bool) bool {
+		if !fn(string(word)) {
+			return false
+		}
+
+		found++
+		return max <= 0 || found < max
+	})
+}
+
+// StreamFrom is like SearchFrom, but sends the words on the returned channel, which is closed once the search is
+// over. To abandon the search early, cancel ctx; at most one more word is then sent before the channel is closed.
+func (d *Dictionary) StreamFrom(ctx context.Context, chars string, max int) <-chan string {
+	result := make(chan string)
+
+	go func() {
+		defer close(result)
+
+		d.SearchFrom(ctx, chars, max, func(word string) bool {
+			// select picks at random when both cases are ready, so don't leave it to select to notice cancellation
+			if ctx.Err() != nil {
+				return false
+			}
+
+			select {
+			case result <- word:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	return result
+}
+
+// SearchWordsFrom is like WordsFrom, but streams the words from the dictionary for DefaultLanguage, as for
+// Dictionary.SearchFrom.
+func SearchWordsFrom(ctx context.Context, chars string, max int, fn func(word string) bool) error {
+	return defaultLanguageDictionary().SearchFrom(ctx, chars, max, fn)
+}
+
+// StreamWordsFrom is like WordsFrom, but streams the words from the dictionary for DefaultLanguage, as for
+// Dictionary.StreamFrom.
+func StreamWordsFrom(ctx context.Context, chars string, max int) <-chan string {
+	return defaultLanguageDictionary().StreamFrom(ctx, chars, max)
+}
@@ -0,0 +1,98 @@
+package gocarina
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestSearchFrom(t *testing.T) {
+	d := NewDictionary([]string{"bar", "bare", "bear", "ear", "era", "beard"})
+
+	var examples = []struct {
+		max      int
+		expected []string
+	}{
+		{0, []string{"bar", "bare", "bear", "ear", "era"}},
+		{2, []string{"bar", "bare"}},
+		{10, []string{"bar", "bare", "bear", "ear", "era"}},
+	}
+
+	for _, ex := range examples {
+		var actual []string
+		err := d.SearchFrom(context.Background(), "bear", ex.max, func(word string) bool {
+			actual = append(actual, word)
+			return true
+		})
+
+		if err != nil {
+			t.Errorf("max %d: %s", ex.max, err)
+		}
+
+		if !reflect.DeepEqual(ex.expected, actual) {
+			t.Errorf("max %d: expected: %v, got: %v", ex.max, ex.expected, actual)
+		}
+	}
+
+	// returning false stops the search, without an error
+	count := 0
+	err := d.SearchFrom(context.Background(), "bear", 0, func(word string) bool {
+		count++
+		return count < 3
+	})
+
+	if err != nil || count != 3 {
+		t.Errorf("expected to stop after 3 words without error, got %d words and: %v", count, err)
+	}
+}
+
+func TestSearchFromCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	count := 0
+	err := SearchWordsFrom(ctx, benchmarkLetters, 0, func(word string) bool {
+		if count++; count == 10 {
+			cancel()
+		}
+		return true
+	})
+
+	if err != context.Canceled {
+		t.Errorf("expected %v, got: %v", context.Canceled, err)
+	}
+
+	if count >= len(WordsFrom(benchmarkLetters)) {
+		t.Errorf("expected the search to stop early, got all %d words", count)
+	}
+
+	if err := SearchWordsFrom(ctx, "bear", 0, func(string) bool { return true }); err != context.Canceled {
+		t.Errorf("expected a search with a done context not to start, got: %v", err)
+	}
+}
+
+func TestStreamFrom(t *testing.T) {
+	var actual []string
+	for word := range StreamWordsFrom(context.Background(), "BEAR", 0) {
+		actual = append(actual, word)
+	}
+
+	if !reflect.DeepEqual(Rank(actual, LengthRanker, 0), WordsFrom("bear")) {
+		t.Errorf("expected the same words as WordsFrom, got: %v", actual)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	words := StreamWordsFrom(ctx, benchmarkLetters, 0)
+
+	<-words
+	cancel()
+
+	// the channel must be closed soon after cancelling, rather than sending every word
+	count := 0
+	for range words {
+		count++
+	}
+
+	if count > 1 {
+		t.Errorf("expected at most one more word after cancelling, got: %d", count)
+	}
+}
@@ -3,25 +3,164 @@ package gocarina
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"log"
+	"unicode"
 )
 
+// Normalization determines how a bounded glyph is fitted into the TileTargetWidth x TileTargetHeight bitmap
+// that is fed to the network.
+type Normalization int
+
+const (
+	NormalizeStretch Normalization = iota // stretch the glyph to fill the bitmap, distorting its aspect ratio
+	NormalizeAspect                       // preserve the aspect ratio, and centre the glyph on its centre of mass
+)
+
+// Wildcard is the letter of a tile that hasn't been recognized. In word searches it can stand for any letter, or for
+// any of the tile's Candidates, if it has them.
+const Wildcard = '?'
+
+// Owner identifies which player, if any, has claimed a tile.
+type Owner int
+
+const (
+	Unowned Owner = iota
+	Blue
+	Red
+)
+
+func (o Owner) String() string {
+	switch o {
+	case Blue:
+		return "blue"
+	case Red:
+		return "red"
+	}
+
+	return "unowned"
+}
+
+// Opponent returns the other player. Unowned tiles have no opponent.
+func (o Owner) Opponent() Owner {
+	switch o {
+	case Blue:
+		return Red
+	case Red:
+		return Blue
+	}
+
+	return Unowned
+}
+
+// tileColors are the tile backgrounds in Letterpress's default theme. Locked (defended) tiles are shown in a darker
+// shade of their owner's color. Unowned tiles alternate between two shades of grey.
+var tileColors = []struct {
+	color  color.RGBA
+	owner  Owner
+	locked bool
+}{
+	{color.RGBA{233, 232, 229, 0xff}, Unowned, false},
+	{color.RGBA{230, 229, 226, 0xff}, Unowned, false},
+	{color.RGBA{120, 200, 245, 0xff}, Blue, false},
+	{color.RGBA{0, 162, 255, 0xff}, Blue, true},
+	{color.RGBA{247, 153, 141, 0xff}, Red, false},
+	{color.RGBA{255, 67, 47, 0xff}, Red, true},
+}
+
 // Tile represents a lettered square from a Letterpress game board.
 type Tile struct {
-	Letter  rune        // the letter this tile represents, if known
-	img     image.Image // the original tile image, prior to any scaling/downsampling
-	Reduced image.Image // the tile in black and white, bounding-boxed, and scaled down
-	Bounded image.Image // the bounded tile (used only for debugging)
+	Letter        rune          // the letter this tile represents, if known
+	Candidates    []rune        // for a Wildcard tile, the letters it most likely represents; if empty, it could be any
+	Owner         Owner         // the player that has claimed this tile, if any
+	Locked        bool          // whether the tile is defended, i.e. cannot currently be taken by the other player
+	img           image.Image   // the original tile image, prior to any scaling/downsampling
+	Reduced       image.Image   // the tile in black and white, bounding-boxed, and scaled down
+	Bounded       image.Image   // the bounded tile (used only for debugging)
+	Normalization Normalization // how Bounded was scaled down to Reduced
 }
 
 func NewTile(letter rune, img image.Image) (result *Tile) {
 	result = &Tile{Letter: letter, img: img}
+	result.Owner, result.Locked = classifyTile(img)
 	result.reduce(0)
 
 	return
 }
 
-// Reduce the tile by converting to monochrome, applying a bounding box, and scaling to match the given size.
+// Matches returns true if the tile can be played as the letter c: either it shows c, or it is a Wildcard that may
+// stand for c.
+func (t *Tile) Matches(c rune) bool {
+	c = unicode.ToLower(c)
+
+	if t.Letter != Wildcard {
+		return unicode.ToLower(t.Letter) == c
+	}
+
+	if len(t.Candidates) == 0 {
+		return true
+	}
+
+	for _, candidate := range t.Candidates {
+		if unicode.ToLower(candidate) == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// classifyTile determines the state of a tile from its background color, which is taken to be the most common
+// color among the pixels that are not part of the letter.
+func classifyTile(img image.Image) (owner Owner, locked bool) {
+	b := img.Bounds()
+	bw := BlackWhiteImage(img)
+	counts := make(map[color.RGBA]int)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if IsBlack(bw.At(x, y)) {
+				continue
+			}
+
+			r, g, bl, _ := img.At(x, y).RGBA()
+			counts[color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(bl >> 8), 0xff}]++
+		}
+	}
+
+	var background color.RGBA
+	best := -1
+	for c, n := range counts {
+		if n > best || (n == best && colorLess(c, background)) {
+			background, best = c, n
+		}
+	}
+
+	// pick the closest of the known tile colors
+	closest := -1
+	for _, tc := range tileColors {
+		d := colorDistance(background, tc.color)
+		if closest < 0 || d < closest {
+			closest = d
+			owner, locked = tc.owner, tc.locked
+		}
+	}
+
+	return
+}
+
+// Normalize re-reduces the tile using the given normalization, if it was not already reduced that way.
+// Tiles must be reduced the same way for training and recognition, so see also Network.Normalization.
+func (t *Tile) Normalize(mode Normalization) {
+	if t.Normalization == mode && t.Reduced != nil {
+		return
+	}
+
+	t.Normalization = mode
+	t.reduce(0)
+}
+
+// Reduce the tile by converting to monochrome (inverting light-on-dark tiles), applying a bounding box, and scaling to match the given size.
 // The resulting image will be stored in t.Reduced.
 func (t *Tile) reduce(border int) {
 	targetRect := image.Rect(0, 0, TileTargetWidth, TileTargetHeight)
@@ -33,10 +172,21 @@ func (t *Tile) reduce(border int) {
 		log.Fatalf("expected targetRect.Dy() to be %d, got: %d", TileTargetHeight, targetRect.Dy())
 	}
 
-	src := BlackWhiteImage(t.img)
+	src := NormalizePolarity(t.img)
 
-	// find the bounding box for the character
-	bbox := BoundingBox(src, border)
+	// find the bounding box for the character, ignoring any specks of noise
+	minArea := int(SpeckAreaPercent * float64(t.img.Bounds().Dx()*t.img.Bounds().Dy()))
+	bbox := GlyphBoundingBox(src, minArea, border)
+
+	if t.Normalization == NormalizeAspect {
+		// the aspect ratio is preserved, so skinny letters are safe to bound
+		t.Bounded = src.(interface {
+			SubImage(r image.Rectangle) image.Image
+		}).SubImage(bbox)
+		t.Reduced = ScaleToFit(t.Bounded, targetRect)
+
+		return
+	}
 
 	// Only apply the bounding box if it's above some % of the width/height of original tile.
 	// This is to avoid pathological cases for skinny letters like "I", which
@@ -0,0 +1,111 @@
+package gocarina
+
+import (
+	"image"
+	"image/draw"
+	"testing"
+	"unicode"
+)
+
+func TestNormalizeAspect(t *testing.T) {
+	// a tall, skinny 'I'
+	img := image.NewRGBA(image.Rect(0, 0, 128, 128))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+	draw.Draw(img, image.Rect(60, 30, 68, 90), image.Black, image.ZP, draw.Src)
+
+	tile := NewTile('I', img)
+	tile.Normalize(NormalizeAspect)
+
+	if tile.Normalization != NormalizeAspect {
+		t.Fatalf("expected tile to be normalized with NormalizeAspect")
+	}
+
+	bbox := BoundingBox(tile.Reduced, 0)
+
+	// the glyph keeps its aspect ratio, filling the height less the margin...
+	assertHeight(bbox, TileTargetHeight-2, t)
+	assertWidth(bbox, 1, t)
+
+	// ...and is centred
+	if bbox.Min.X != TileTargetWidth/2-1 && bbox.Min.X != TileTargetWidth/2 {
+		t.Errorf("expected glyph to be centred, got bounding box: %v", bbox)
+	}
+
+	if bbox.Min.Y != 1 {
+		t.Errorf("expected glyph to start after the margin, got bounding box: %v", bbox)
+	}
+}
+
+func TestNormalizeStretch(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 128, 128))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+	draw.Draw(img, image.Rect(30, 40, 90, 90), image.Black, image.ZP, draw.Src)
+
+	tile := NewTile('-', img)
+
+	// the default normalization stretches the glyph to fill the whole target
+	bbox := BoundingBox(tile.Reduced, 0)
+	assertWidth(bbox, TileTargetWidth, t)
+	assertHeight(bbox, TileTargetHeight, t)
+}
+
+func TestTileOwner(t *testing.T) {
+	// b/r are blue/red, B/R are locked blue/red, and . is unowned
+	var examples = []struct {
+		file   string
+		owners string
+	}{
+		{"board-images/board1.png", "bbrb. rr... rRr.b brb.r Bbr.."},
+		{"board-images/board4.png", "brbb. br.rr b.bbb BbBbr BBbrR"},
+	}
+
+	for _, tt := range examples {
+		b := ReadUnknownBoard(tt.file)
+
+		var actual []rune
+		for i, tile := range b.Tiles {
+			if i > 0 && i%b.Cols() == 0 {
+				actual = append(actual, ' ')
+			}
+
+			c := '.'
+			switch tile.Owner {
+			case Blue:
+				c = 'b'
+			case Red:
+				c = 'r'
+			}
+
+			if tile.Locked {
+				c = unicode.ToUpper(c)
+			}
+
+			actual = append(actual, c)
+		}
+
+		if string(actual) != tt.owners {
+			t.Errorf("%s: expected owners %q, got: %q", tt.file, tt.owners, string(actual))
+		}
+	}
+}
+
+func TestTileMatches(t *testing.T) {
+	var examples = []struct {
+		tile     Tile
+		letter   rune
+		expected bool
+	}{
+		{Tile{Letter: 'A'}, 'a', true},
+		{Tile{Letter: 'A'}, 'B', false},
+		{Tile{Letter: Wildcard}, 'z', true},
+		{Tile{Letter: Wildcard, Candidates: []rune{'O', 'Q'}}, 'q', true},
+		{Tile{Letter: Wildcard, Candidates: []rune{'O', 'Q'}}, 'c', false},
+	}
+
+	for _, ex := range examples {
+		if actual := ex.tile.Matches(ex.letter); actual != ex.expected {
+			t.Errorf("%c with candidates %q, matching %c: expected %t, got: %t",
+				ex.tile.Letter, string(ex.tile.Candidates), ex.letter, ex.expected, actual)
+		}
+	}
+}